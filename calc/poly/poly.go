@@ -271,6 +271,68 @@ func (p Poly) Roots(buf []float64) []float64 {
 	return roots
 }
 
+// RootsIn finds the real roots of the polynomial in the range [min, max] in
+// ascending order. Each root is isolated between the roots of the derivative
+// and found by bisection, so unlike Roots it will not miss roots of higher order
+// polynomials. Roots where the polynomial touches zero without changing sign are
+// only found if the value is exactly zero.
+func (p Poly) RootsIn(min, max float64) []float64 {
+	p = p.Copy(nil)
+	ln := p.Len()
+	for ln > 0 && p.Coefficient(ln-1) == 0 {
+		ln--
+	}
+	if ln < 2 {
+		return nil
+	}
+	p = Poly{Slice(p.Buf()[:ln])}
+
+	bounds := []float64{min}
+	if ln > 2 {
+		bounds = append(bounds, p.D().RootsIn(min, max)...)
+	}
+	bounds = append(bounds, max)
+
+	var out []float64
+	prev := bounds[0]
+	fPrev := p.F(prev)
+	if fPrev == 0 {
+		out = append(out, prev)
+	}
+	for _, b := range bounds[1:] {
+		fb := p.F(b)
+		if fb == 0 {
+			if len(out) == 0 || out[len(out)-1] != b {
+				out = append(out, b)
+			}
+		} else if fPrev != 0 && (fPrev < 0) != (fb < 0) {
+			out = append(out, p.bisect(prev, b, fPrev))
+		}
+		prev, fPrev = b, fb
+	}
+	return out
+}
+
+// bisect finds the root between a and b where fa is p(a) and p(b) has the
+// opposite sign.
+func (p Poly) bisect(a, b, fa float64) float64 {
+	for {
+		m := (a + b) / 2
+		if m <= a || m >= b {
+			return m
+		}
+		fm := p.F(m)
+		if fm == 0 {
+			return m
+		}
+		if (fm < 0) == (fa < 0) {
+			a, fa = m, fm
+		} else {
+			b = m
+		}
+	}
+}
+
 // Newton's method to find one root of the polynomial. The initial guess is
 // passed in as x; min sets how close to 0 is acceptible and it will return if a
 // value closer than that is found; steps limits the maximum number of
@@ -393,6 +393,25 @@ func TestRoots(t *testing.T) {
 	assert.Equal(t, []float64{1}, p.Roots(nil))
 }
 
+func TestRootsIn(t *testing.T) {
+	// (x-0.1)(x-0.2)...(x-0.7)
+	p := poly.New(1)
+	for i := 1.0; i < 8; i++ {
+		p = p.Multiply(poly.New(-i/10, 1)).Copy(nil)
+	}
+	roots := p.RootsIn(0, 1)
+	if assert.Len(t, roots, 7) {
+		for i, r := range roots {
+			assert.InDelta(t, float64(i+1)/10, r, 1e-12)
+		}
+	}
+	assert.Len(t, p.RootsIn(0.15, 0.45), 3)
+
+	assert.Equal(t, []float64{0, 1}, poly.New(0, -1, 1).RootsIn(0, 1))
+	assert.Nil(t, poly.New(1).RootsIn(0, 1))
+	assert.Nil(t, poly.New(1, 0, 1).RootsIn(-5, 5))
+}
+
 func TestQuad(t *testing.T) {
 	tt := map[string]struct {
 		expected []float64
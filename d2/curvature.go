@@ -0,0 +1,100 @@
+package d2
+
+import (
+	"math"
+)
+
+// Curvature of the curve at t0. It is positive when the curve is turning
+// counter-clockwise and negative when it is turning clockwise. The radius of
+// curvature is the reciprocal.
+func Curvature(c Pt1, t0 float64) float64 {
+	v1 := GetV1(c).V1(t0)
+	m := v1.Mag()
+	if m == 0 {
+		return 0
+	}
+	return v1.Cross(GetV2(c).V2(t0)) / (m * m * m)
+}
+
+// Normal returns the unit vector perpendicular to the curve at t0. It is the
+// tangent rotated counter-clockwise, so when the Curvature is positive it
+// points towards the center of curvature.
+func Normal(c Pt1, t0 float64) V {
+	v1 := GetV1(c).V1(t0)
+	m := v1.Mag()
+	if m == 0 {
+		return V{}
+	}
+	return V{-v1.Y / m, v1.X / m}
+}
+
+// OsculatingCircle returns the center and radius of the circle that best
+// approximates the curve at t0. If the curvature is zero, the center is the
+// point on the curve and the radius is infinite.
+func OsculatingCircle(c Pt1, t0 float64) (Pt, float64) {
+	pt := c.Pt1(t0)
+	k := Curvature(c, t0)
+	if k == 0 {
+		return pt, math.Inf(1)
+	}
+	return pt.Add(Normal(c, t0).Multiply(1 / k)), math.Abs(1 / k)
+}
+
+// Inflectioner returns the parametric values in the range [0,1] where the
+// curvature changes sign.
+type Inflectioner interface {
+	Inflections() []float64
+}
+
+// InflectionSteps is the number of samples used to find inflection points when
+// the curve does not fulfill Inflectioner.
+var InflectionSteps = 100
+
+// Inflections returns the parametric values in the range [0,1] where the
+// curvature of the curve changes sign. If the curve fulfills Inflectioner that
+// is used, otherwise the curve is sampled and sign changes are refined by
+// bisection.
+func Inflections(c Pt1) []float64 {
+	if i, ok := c.(Inflectioner); ok {
+		return i.Inflections()
+	}
+	v1, v2 := GetV1(c), GetV2(c)
+	cross := func(t float64) float64 {
+		return v1.V1(t).Cross(v2.V2(t))
+	}
+
+	// Points where the cross product is exactly zero are skipped so that a
+	// straight section does not report inflections.
+	var out []float64
+	step := 1.0 / float64(InflectionSteps)
+	t0 := 0.0
+	c0 := cross(t0)
+	for i := 1; i <= InflectionSteps; i++ {
+		t1 := float64(i) * step
+		c1 := cross(t1)
+		if c1 == 0 {
+			continue
+		}
+		if c0 != 0 && (c0 < 0) != (c1 < 0) {
+			out = append(out, bisect(cross, t0, t1, c0))
+		}
+		t0, c0 = t1, c1
+	}
+	return out
+}
+
+func bisect(fn func(float64) float64, t0, t1, f0 float64) float64 {
+	for i := 0; i < 50; i++ {
+		m := (t0 + t1) / 2
+		fm := fn(m)
+		if fm == 0 {
+			return m
+		}
+		if (fm < 0) == (f0 < 0) {
+			t0, f0 = m, fm
+		} else {
+			t1 = m
+		}
+	}
+	return (t0 + t1) / 2
+}
@@ -0,0 +1,96 @@
+package d2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCircle float64
+
+func (c mockCircle) Pt1(t0 float64) Pt {
+	s, cs := math.Sincos(t0 * 2 * math.Pi)
+	r := float64(c)
+	return Pt{r * cs, r * s}
+}
+
+func (c mockCircle) V1(t0 float64) V {
+	s, cs := math.Sincos(t0 * 2 * math.Pi)
+	r := float64(c) * 2 * math.Pi
+	return V{-r * s, r * cs}
+}
+
+func (c mockCircle) V2(t0 float64) V {
+	s, cs := math.Sincos(t0 * 2 * math.Pi)
+	r := float64(c) * 4 * math.Pi * math.Pi
+	return V{-r * cs, -r * s}
+}
+
+type mockCubic struct{}
+
+func (mockCubic) Pt1(t0 float64) Pt {
+	x := t0 - 0.3
+	return Pt{t0, x * x * x}
+}
+
+func (mockCubic) V1(t0 float64) V {
+	x := t0 - 0.3
+	return V{1, 3 * x * x}
+}
+
+func (mockCubic) V2(t0 float64) V {
+	return V{0, 6 * (t0 - 0.3)}
+}
+
+type mockPt1Only struct {
+	p Pt1
+}
+
+func (m mockPt1Only) Pt1(t0 float64) Pt {
+	return m.p.Pt1(t0)
+}
+
+func TestV2Wrapper(t *testing.T) {
+	w := V2Wrapper{V1Wrapper{mockPt1{}, 1e-5}, 1e-5}
+	vApproxEqual(t, V{0, 2}, w.V2(0.1))
+	vApproxEqual(t, V{0, 2}, w.V2(0.7))
+
+	_, ok := GetV2(mockPt1{}).(V2Wrapper)
+	assert.True(t, ok)
+	_, ok = GetV2(mockCircle(1)).(mockCircle)
+	assert.True(t, ok)
+
+	geomtest.Equal(t, AssertV2{}, mockCircle(2))
+	assert.Error(t, AssertV2{}.AssertEqual(mockPt1{}, 1e-10))
+}
+
+func TestCurvature(t *testing.T) {
+	c := mockCircle(2)
+	for i := 0.0; i < 1.0; i += 0.1 {
+		geomtest.Equal(t, 0.5, Curvature(c, i))
+		geomtest.Equal(t, c.Pt1(i).V().Multiply(-0.5), Normal(c, i))
+		center, r := OsculatingCircle(c, i)
+		geomtest.Equal(t, Pt{0, 0}, center)
+		geomtest.Equal(t, 2.0, r)
+	}
+
+	assert.InDelta(t, 0.5, Curvature(mockPt1{}, 0), 1e-4)
+	geomtest.EqualInDelta(t, V{-1, 2}.Multiply(1/math.Sqrt(5)), Normal(mockPt1{}, 0.5), 1e-6)
+	pt, r := OsculatingCircle(mockCubic{}, 0.3)
+	assert.True(t, math.IsInf(r, 1))
+	geomtest.Equal(t, Pt{0.3, 0}, pt)
+}
+
+func TestInflections(t *testing.T) {
+	ts := Inflections(mockCubic{})
+	if assert.Len(t, ts, 1) {
+		assert.InDelta(t, 0.3, ts[0], 1e-10)
+	}
+	ts = Inflections(mockPt1Only{mockCubic{}})
+	if assert.Len(t, ts, 1) {
+		assert.InDelta(t, 0.3, ts[0], 1e-4)
+	}
+	assert.Len(t, Inflections(mockCircle(1)), 0)
+}
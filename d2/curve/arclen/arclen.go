@@ -0,0 +1,200 @@
+// Package arclen reparameterizes curves by arc length so that equal steps in
+// the parametric value are equal steps in distance along the curve.
+package arclen
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/calc/cmpr"
+	"github.com/adamcolton/geom/d2"
+)
+
+// DefaultTolerance is used by New if the provided tolerance is 0.
+var DefaultTolerance = cmpr.Tolerance(1e-9)
+
+// MaxDepth limits how many times an interval will be subdivided when measuring
+// a curve.
+var MaxDepth = 20
+
+// ArcLength wraps a curve so that the parametric value maps linearly to the
+// distance along the curve. Pt1(0.5) is the point halfway along the length of
+// the curve.
+type ArcLength struct {
+	curve  d2.Pt1
+	v1     d2.V1
+	tol    cmpr.Tolerance
+	ts, ds []float64
+}
+
+// New measures the curve c and returns an ArcLength. The tolerance controls
+// the accuracy of the length measurement and the parametric inversion.
+func New(c d2.Pt1, tol cmpr.Tolerance) *ArcLength {
+	if tol <= 0 {
+		tol = DefaultTolerance
+	}
+	a := &ArcLength{
+		curve: c,
+		v1:    d2.GetV1(c),
+		tol:   tol,
+		ts:    []float64{0},
+		ds:    []float64{0},
+	}
+	s := a.integrate(0, 1)
+	a.measure(0, 1, s, float64(tol), 0)
+	return a
+}
+
+// measure recursively subdivides [t0,t1] until the quadrature of the whole
+// interval agrees with the sum of the quadrature of each half. The end of each
+// accepted interval is appended to the lookup table.
+func (a *ArcLength) measure(t0, t1, whole, tol float64, depth int) {
+	m := (t0 + t1) / 2
+	l, r := a.integrate(t0, m), a.integrate(m, t1)
+	if depth >= MaxDepth || math.Abs(l+r-whole) <= tol {
+		d := a.ds[len(a.ds)-1]
+		a.ts = append(a.ts, m, t1)
+		a.ds = append(a.ds, d+l, d+l+r)
+		return
+	}
+	a.measure(t0, m, l, tol/2, depth+1)
+	a.measure(m, t1, r, tol/2, depth+1)
+}
+
+// Five point Gauss-Legendre nodes and weights on [-1,1].
+var (
+	glX = [5]float64{
+		-0.9061798459386640,
+		-0.5384693101056831,
+		0,
+		0.5384693101056831,
+		0.9061798459386640,
+	}
+	glW = [5]float64{
+		0.2369268850561891,
+		0.4786286704993665,
+		0.5688888888888889,
+		0.4786286704993665,
+		0.2369268850561891,
+	}
+)
+
+// integrate the speed of the curve from t0 to t1.
+func (a *ArcLength) integrate(t0, t1 float64) float64 {
+	h := (t1 - t0) / 2
+	c := (t1 + t0) / 2
+	var sum float64
+	for i, x := range glX {
+		sum += glW[i] * a.v1.V1(c+h*x).Mag()
+	}
+	return sum * h
+}
+
+// Length of the curve.
+func (a *ArcLength) Length() float64 {
+	return a.ds[len(a.ds)-1]
+}
+
+// LengthAt returns the distance along the underlying curve from 0 to the
+// parametric value t relative to the underlying curve.
+func (a *ArcLength) LengthAt(t float64) float64 {
+	if t <= 0 {
+		return a.integrate(0, t)
+	}
+	if t >= 1 {
+		return a.Length() + a.integrate(1, t)
+	}
+	idx := sort.SearchFloat64s(a.ts, t)
+	if a.ts[idx] == t {
+		return a.ds[idx]
+	}
+	idx--
+	return a.ds[idx] + a.integrate(a.ts[idx], t)
+}
+
+// TAtLength returns the parametric value on the underlying curve at distance d
+// along the curve. Distances outside the range [0,Length] are extrapolated
+// from the ends of the curve.
+func (a *ArcLength) TAtLength(d float64) float64 {
+	ln := len(a.ds)
+	if d <= 0 {
+		return a.extrapolate(0, d)
+	}
+	if l := a.ds[ln-1]; d >= l {
+		return a.extrapolate(1, d-l)
+	}
+	idx := sort.SearchFloat64s(a.ds, d)
+	if a.ds[idx] == d {
+		return a.ts[idx]
+	}
+	lo, hi := a.ts[idx-1], a.ts[idx]
+	d -= a.ds[idx-1]
+	t0 := lo
+
+	// Newton's method, falling back to bisection if a step leaves the bracket.
+	t := lo + (hi-lo)*d/(a.ds[idx]-a.ds[idx-1])
+	for i := 0; i < 50; i++ {
+		f := a.integrate(t0, t) - d
+		if a.tol.Zero(f) {
+			break
+		}
+		if f > 0 {
+			hi = t
+		} else {
+			lo = t
+		}
+		s := a.v1.V1(t).Mag()
+		next := t - f/s
+		if s == 0 || next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		t = next
+	}
+	return t
+}
+
+func (a *ArcLength) extrapolate(t, d float64) float64 {
+	s := a.v1.V1(t).Mag()
+	if s == 0 {
+		return t
+	}
+	return t + d/s
+}
+
+// Pt1 fulfills d2.Pt1. The distance from Pt1(0) to Pt1(t) along the curve is
+// t*Length().
+func (a *ArcLength) Pt1(t float64) d2.Pt {
+	return a.curve.Pt1(a.TAtLength(t * a.Length()))
+}
+
+// V1 fulfills d2.V1. The magnitude of the derivative is always equal to the
+// length of the curve.
+func (a *ArcLength) V1(t float64) d2.V {
+	v := a.v1.V1(a.TAtLength(t * a.Length()))
+	m := v.Mag()
+	if m == 0 {
+		return v
+	}
+	return v.Multiply(a.Length() / m)
+}
+
+// Curve returns the underlying curve.
+func (a *ArcLength) Curve() d2.Pt1 {
+	return a.curve
+}
+
+// L fulfills d2.Limiter.
+func (*ArcLength) L(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// VL fulfills d2.VLimiter.
+func (*ArcLength) VL(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
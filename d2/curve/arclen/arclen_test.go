@@ -0,0 +1,62 @@
+package arclen
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLine(t *testing.T) {
+	l := line.New(d2.Pt{1, 1}, d2.Pt{4, 5})
+	a := New(l, 0)
+	geomtest.Equal(t, 5.0, a.Length())
+	for i := 0.0; i <= 1.0; i += 0.1 {
+		geomtest.Equal(t, l.Pt1(i), a.Pt1(i))
+		geomtest.Equal(t, i, a.TAtLength(i*5))
+	}
+}
+
+func TestCircle(t *testing.T) {
+	e := ellipsearc.New(d2.Pt{0, 0}, d2.Pt{0, 0}, 2)
+	a := New(e, 1e-10)
+	geomtest.EqualInDelta(t, 4*math.Pi, a.Length(), 1e-8)
+	geomtest.EqualInDelta(t, 2*math.Pi, a.LengthAt(0.5), 1e-8)
+}
+
+func TestBezier(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 10}, {1, 10}, {10, 0}}
+	a := New(b, 1e-10)
+
+	ln := a.Length()
+	step := 0.05
+	prev := a.Pt1(0)
+	geomtest.Equal(t, b[0], prev)
+	geomtest.Equal(t, b[3], a.Pt1(1))
+	for i := step; i <= 1.0; i += step {
+		// each chord should be slightly shorter than the arc
+		cur := a.Pt1(i)
+		d := cur.Distance(prev)
+		assert.True(t, d <= ln*step+1e-9)
+		assert.InDelta(t, ln*step, d, ln*step*0.05)
+		geomtest.EqualInDelta(t, i*ln, a.LengthAt(a.TAtLength(i*ln)), 1e-8)
+		geomtest.EqualInDelta(t, ln, a.V1(i).Mag(), 1e-8)
+		prev = cur
+	}
+
+	geomtest.EqualInDelta(t, d2.AssertV1{}, a, 1e-6)
+}
+
+func TestExtrapolate(t *testing.T) {
+	l := line.New(d2.Pt{0, 0}, d2.Pt{0, 2})
+	a := New(l, 0)
+	geomtest.Equal(t, -0.5, a.TAtLength(-1))
+	geomtest.Equal(t, 1.5, a.TAtLength(3))
+	assert.Equal(t, d2.LimitBounded, a.L(1, 1))
+	assert.Equal(t, d2.LimitUndefined, a.L(2, 1))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/adamcolton/geom/calc/comb"
 	"github.com/adamcolton/geom/d2"
 	"github.com/adamcolton/geom/d2/affine"
+	"github.com/adamcolton/geom/d2/curve/poly"
 )
 
 // Bezier curve defined by a slice of control points
@@ -58,6 +59,36 @@ func (b Bezier) V1(t float64) d2.V {
 	return b.Tangent().V1(t)
 }
 
+// V2 fulfills d2.V2 and returns the second derivative at t.
+func (b Bezier) V2(t float64) d2.V {
+	if len(b) < 3 {
+		return d2.V{}
+	}
+	return b.Tangent().Bezier.Tangent().V1(t)
+}
+
+// Inflections fulfills d2.Inflectioner and returns the parametric values in the
+// range [0,1] where the curvature changes sign.
+func (b Bezier) Inflections() []float64 {
+	if len(b) < 3 {
+		return nil
+	}
+	return poly.NewBezier(b).Inflections()
+}
+
+// Nearest returns the parametric value in the range [0,1] of the point on the
+// curve closest to pt and the distance to that point.
+func (b Bezier) Nearest(pt d2.Pt) (float64, float64) {
+	if len(b) < 2 {
+		if len(b) == 0 {
+			return 0, math.Inf(1)
+		}
+		return 0, b[0].Distance(pt)
+	}
+	t, _ := poly.NewBezier(b).Nearest(pt)
+	return t, b.Pt1(t).Distance(pt)
+}
+
 // L fulfills d2.Limiter.
 func (Bezier) L(t, c int) d2.Limit {
 	if t == 1 && c == 1 {
@@ -121,3 +152,29 @@ func (bc BezierCache) V1(t float64) d2.V {
 func (bc BezierCache) V1c0() d2.V1 {
 	return bc
 }
+
+// Reverse returns the Bezier curve with the control points in the opposite
+// order.
+func (b Bezier) Reverse() Bezier {
+	out := make(Bezier, len(b))
+	for i, pt := range b {
+		out[len(b)-1-i] = pt
+	}
+	return out
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (b Bezier) Pt1Reverse() d2.Pt1 {
+	return b.Reverse()
+}
+
+// T applies the transform to the control points. A Bezier curve is invariant
+// under affine transforms so this is exact.
+func (b Bezier) T(t *d2.T) Bezier {
+	return t.Slice(b)
+}
+
+// Pt1T fulfills d2.Transformer.
+func (b Bezier) Pt1T(t *d2.T) d2.Pt1 {
+	return b.T(t)
+}
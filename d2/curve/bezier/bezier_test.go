@@ -1,10 +1,13 @@
 package bezier
 
 import (
+	"math"
 	"testing"
 
 	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
 	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape/polygon"
 	"github.com/adamcolton/geom/geomtest"
 	"github.com/stretchr/testify/assert"
 )
@@ -98,3 +101,210 @@ func TestBlossomAndSegment(t *testing.T) {
 	geomtest.Equal(t, b.Pt1(0.5), s.Pt1(0.5))
 	geomtest.Equal(t, b.Pt1(0.75), s.Pt1(1))
 }
+
+func TestBezierIntersections(t *testing.T) {
+	b := Bezier{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	b2 := Bezier{{-1, 0.2}, {0.5, 0.6}, {2, 0.2}}
+
+	is := b.Intersections(b2, nil)
+	assert.Len(t, is, 2)
+	for _, ts := range is {
+		geomtest.EqualInDelta(t, b.Pt1(ts[0]), b2.Pt1(ts[1]), 1e-9)
+	}
+
+	one := b.Intersections(b2, make([][2]float64, 1))
+	assert.Len(t, one, 1)
+
+	// An S curve crossing a line-like curve three times.
+	s := Bezier{{0, 0}, {166, 1000}, {333, -500}, {500, 500}}
+	l := Bezier{{0, 200}, {500, 300}}
+	is = s.Intersections(l, nil)
+	assert.Len(t, is, 3)
+	for _, ts := range is {
+		geomtest.EqualInDelta(t, s.Pt1(ts[0]), l.Pt1(ts[1]), 1e-8)
+	}
+
+	assert.Len(t, b.Intersections(Bezier{{5, 5}, {6, 6}}, nil), 0)
+}
+
+func TestBezierIntersectionsOverlap(t *testing.T) {
+	c := Bezier{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	for _, o := range []Bezier{c, c.Segment(0.2, 0.9), c.Segment(0.5, 1.5)} {
+		is := c.Intersections(o, nil)
+		for _, ts := range is {
+			geomtest.EqualInDelta(t, c.Pt1(ts[0]), o.Pt1(ts[1]), 1e-9)
+		}
+	}
+
+	// flat pieces that cross are still found
+	l := Bezier{{0.5, 0}, {0.5, 2}}
+	is := c.Segment(0.2, 0.9).Intersections(l, nil)
+	assert.Len(t, is, 1)
+}
+
+func BenchmarkBezierIntersectionsOverlap(b *testing.B) {
+	c := Bezier{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	o := c.Segment(0.2, 0.9)
+	for i := 0; i < b.N; i++ {
+		c.Intersections(o, nil)
+	}
+}
+
+func TestEllipseArcIntersections(t *testing.T) {
+	e := ellipsearc.New(d2.Pt{0, 0}, d2.Pt{2, 0}, 1)
+	b := Bezier{{-1, -2}, {1, 5}, {3, -2}}
+
+	is := b.EllipseArcIntersections(e, nil)
+	assert.Len(t, is, 4)
+	for _, ts := range is {
+		geomtest.EqualInDelta(t, b.Pt1(ts[0]), e.Pt1(ts[1]), 1e-8)
+	}
+
+	e.Start, e.Length = 0, math.Pi
+	is = b.EllipseArcIntersections(e, nil)
+	assert.Len(t, is, 2)
+	for _, ts := range is {
+		geomtest.EqualInDelta(t, b.Pt1(ts[0]), e.Pt1(ts[1]), 1e-8)
+	}
+
+	inside := Bezier{{0.5, 0}, {1, 0.1}, {1.5, 0}}
+	assert.Len(t, inside.EllipseArcIntersections(e, nil), 0)
+}
+
+func TestFlatten(t *testing.T) {
+	b := Bezier{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+	for _, tol := range []float64{1, 0.1, 0.001} {
+		s := b.Flatten(tol)
+		geomtest.Equal(t, b[0], s[0])
+		geomtest.Equal(t, b[3], s[len(s)-1])
+		for i := 0.0; i <= 1.0; i += 0.01 {
+			pt := b.Pt1(i)
+			d := math.Inf(1)
+			for j := range s[1:] {
+				d = math.Min(d, line.New(s[j], s[j+1]).Closest(pt).Distance(pt))
+			}
+			assert.True(t, d <= tol)
+		}
+	}
+	assert.Len(t, Bezier{{0, 0}, {1, 1}, {2, 2}}.Flatten(0.1), 2)
+}
+
+func TestV2(t *testing.T) {
+	geomtest.Equal(t, d2.AssertV2{}, Bezier{{0, 0}, {0.5, 1}, {1, 0}})
+	geomtest.Equal(t, d2.AssertV2{}, Bezier{{0, 0}, {0, 1}, {1, -1}, {1, 0}})
+	geomtest.Equal(t, d2.V{}, Bezier{{0, 0}, {1, 1}}.V2(0.5))
+	geomtest.Equal(t, d2.V{0, -4}, Bezier{{0, 0}, {0.5, 1}, {1, 0}}.V2(0.3))
+}
+
+func TestInflections(t *testing.T) {
+	b := Bezier{{0, 0}, {3, 5}, {-2, 5}, {4, -3}, {5, 5}}
+	ts := b.Inflections()
+	assert.Len(t, ts, 2)
+	nts := d2.Inflections(d2.V1Wrapper{P: b})
+	if assert.Len(t, nts, len(ts)) {
+		for i, tt := range ts {
+			geomtest.EqualInDelta(t, tt, nts[i], 1e-4)
+			assert.InDelta(t, 0, d2.Curvature(b, tt), 1e-9)
+		}
+	}
+
+	assert.Len(t, Bezier{{0, 0}, {0.5, 1}, {1, 0}}.Inflections(), 0)
+	assert.Len(t, Bezier{{0, 0}, {1, 1}}.Inflections(), 0)
+}
+
+func TestNearest(t *testing.T) {
+	b := Bezier{{0, 0}, {0, 10}, {10, -5}, {10, 5}}
+	for _, pt := range []d2.Pt{{5, 5}, {-1, -1}, {11, 7}, {3, 1}, {8, -2}} {
+		tt, d := b.Nearest(pt)
+		geomtest.Equal(t, b.Pt1(tt).Distance(pt), d)
+		// no sampled point should be closer
+		for i := 0.0; i <= 1.0; i += 0.001 {
+			assert.True(t, b.Pt1(i).Distance(pt) >= d-1e-12)
+		}
+		// the closest point is perpendicular to the curve or an end point
+		if tt > 0 && tt < 1 {
+			geomtest.Equal(t, 0.0, b.Pt1(tt).Subtract(pt).Dot(b.V1(tt)))
+		}
+	}
+
+	tt, d := Bezier{{1, 1}}.Nearest(d2.Pt{1, 3})
+	geomtest.Equal(t, 0.0, tt)
+	geomtest.Equal(t, 2.0, d)
+}
+
+func TestSegmentReverseT(t *testing.T) {
+	b := Bezier{{0, 0}, {1, 3}, {3, 3}, {4, 0}}
+	s := d2.Segment(b, 0.2, 0.7)
+	r := d2.Reverse(b)
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	bt := d2.Transform(b, tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, b.Pt1(0.2+i*0.5), s.Pt1(i))
+		geomtest.Equal(t, b.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(b.Pt1(i)), bt.Pt1(i))
+	}
+	assert.IsType(t, Bezier{}, s)
+	assert.IsType(t, Bezier{}, r)
+	assert.IsType(t, Bezier{}, bt)
+}
+
+func TestBoundingBox(t *testing.T) {
+	b := Bezier{{0, 0}, {1, 3}, {3, 3}, {4, 0}}
+	min, max := b.BoundingBox()
+	geomtest.Equal(t, d2.Pt{0, 0}, min)
+	geomtest.Equal(t, d2.Pt{4, 2.25}, max)
+
+	b = Bezier{{0, 0}, {-1, 2}, {3, -2}, {2, 0}}
+	min, max = b.BoundingBox()
+	for i := 0.0; i <= 1.0; i += 0.01 {
+		pt := b.Pt1(i)
+		assert.True(t, pt.X >= min.X-1e-9 && pt.X <= max.X+1e-9)
+		assert.True(t, pt.Y >= min.Y-1e-9 && pt.Y <= max.Y+1e-9)
+	}
+	assert.True(t, min.X > -1 && max.X < 3)
+}
+
+func TestMonotone(t *testing.T) {
+	b := Bezier{{0, 0}, {-1, 2}, {3, -2}, {2, 0}}
+	ms := b.Monotone()
+	assert.Len(t, ms, len(b.Extrema())+1)
+	geomtest.Equal(t, b[0], ms[0][0])
+	geomtest.Equal(t, b[3], ms[len(ms)-1][3])
+	for _, m := range ms {
+		v0 := m.V1(0.5)
+		for i := 0.0; i <= 1.0; i += 0.05 {
+			v := m.V1(i)
+			assert.True(t, v.X*v0.X >= -1e-9)
+			assert.True(t, v.Y*v0.Y >= -1e-9)
+		}
+	}
+
+	assert.Len(t, Bezier{{0, 0}, {1, 1}}.Monotone(), 1)
+}
+
+func TestElevateReduce(t *testing.T) {
+	b := Bezier{{0, 0}, {1, 3}, {3, 3}, {4, 0}}
+	e := b.Elevate()
+	assert.Len(t, e, 5)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, b.Pt1(i), e.Pt1(i))
+	}
+	geomtest.Equal(t, b, e.Reduce())
+	geomtest.Equal(t, b, e.Elevate().Reduce().Reduce())
+
+	// A quadratic can not represent a cubic with an inflection, but the end
+	// points are kept.
+	c := Bezier{{0, 0}, {1, 2}, {2, -2}, {3, 0}}
+	r := c.Reduce()
+	assert.Len(t, r, 3)
+	geomtest.Equal(t, c[0], r[0])
+	geomtest.Equal(t, c[3], r[2])
+
+	geomtest.Equal(t, Bezier{{0, 0}, {2, 0}}, Bezier{{0, 0}, {1, 1}, {2, 0}}.Reduce())
+}
+
+func TestConvexHull(t *testing.T) {
+	b := Bezier{{0, 0}, {1, 3}, {2, 1}, {4, 0}}
+	geomtest.Equal(t, polygon.AssertConvexHuller(b), b)
+	assert.Len(t, b.ConvexHull(), 3)
+}
@@ -0,0 +1,61 @@
+package bezier
+
+import (
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/poly"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+)
+
+// Extrema returns the sorted parametric values in the open range (0,1) where
+// either the X or Y component of the derivative is zero.
+func (b Bezier) Extrema() []float64 {
+	if len(b) < 3 {
+		return nil
+	}
+	d := poly.NewBezier(b.Tangent().Bezier)
+	ts := append(d.X().RootsIn(0, 1), d.Y().RootsIn(0, 1)...)
+	sort.Float64s(ts)
+	out := ts[:0]
+	for _, t := range ts {
+		if t <= 0 || t >= 1 || (len(out) > 0 && t-out[len(out)-1] < 1e-12) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// BoundingBox fulfills shape.BoundingBoxer. The box is found from the end
+// points and the extrema so it is the tightest box that contains the curve,
+// which is usually smaller than the box around the control points.
+func (b Bezier) BoundingBox() (min, max d2.Pt) {
+	if len(b) == 0 {
+		return
+	}
+	pts := []d2.Pt{b[0], b[len(b)-1]}
+	for _, t := range b.Extrema() {
+		pts = append(pts, b.Pt1(t))
+	}
+	return d2.MinMax(pts...)
+}
+
+// Monotone splits the curve at its extrema so that each of the returned curves
+// is monotone in both X and Y.
+func (b Bezier) Monotone() []Bezier {
+	ts := b.Extrema()
+	out := make([]Bezier, 0, len(ts)+1)
+	prev := 0.0
+	for _, t := range ts {
+		out = append(out, b.Segment(prev, t))
+		prev = t
+	}
+	return append(out, b.Segment(prev, 1))
+}
+
+// ConvexHull fulfills shape.ConvexHuller. A Bezier curve is contained by the
+// convex hull of its control points.
+func (b Bezier) ConvexHull() []d2.Pt {
+	return polygon.ConvexHull(append([]d2.Pt(nil), b...)...)
+}
@@ -0,0 +1,85 @@
+package bezier
+
+import (
+	"github.com/adamcolton/geom/d2"
+)
+
+// Elevate returns a Bezier describing the same curve with one more control
+// point.
+func (b Bezier) Elevate() Bezier {
+	n := len(b)
+	if n == 0 {
+		return nil
+	}
+	out := make(Bezier, n+1)
+	out[0], out[n] = b[0], b[n-1]
+	fn := float64(n)
+	for i := 1; i < n; i++ {
+		a := float64(i) / fn
+		out[i] = b[i-1].Multiply(a).Add(b[i].Multiply(1 - a).V())
+	}
+	return out
+}
+
+// Reduce returns a Bezier with one less control point that approximates the
+// curve. The end points are kept and the interior points are chosen so that,
+// when elevated, the result is as close as possible to b in the least squares
+// sense. If b was produced by Elevate, Reduce returns the original curve.
+// Curves with fewer than 3 points are returned unchanged.
+func (b Bezier) Reduce() Bezier {
+	n := len(b)
+	if n < 3 {
+		return append(Bezier(nil), b...)
+	}
+	// The reduced curve q has m+1 points and elevating it gives
+	// r[i] = a(i)*q[i-1] + (1-a(i))*q[i] where a(i) = i/(m+1). Only the
+	// interior points of q are unknown. The normal equations are tridiagonal.
+	m := n - 2
+	out := make(Bezier, m+1)
+	out[0], out[m] = b[0], b[n-1]
+	ln := m - 1
+	if ln == 0 {
+		return out
+	}
+	a := func(i int) float64 { return float64(i) / float64(m+1) }
+
+	lower := make([]float64, ln)
+	diag := make([]float64, ln)
+	upper := make([]float64, ln)
+	rhs := make([]d2.V, ln)
+	for k := range diag {
+		j := k + 1
+		aj, bj := a(j), 1-a(j)
+		aj1, bj1 := a(j+1), 1-a(j+1)
+		diag[k] = bj*bj + aj1*aj1
+		if k > 0 {
+			lower[k] = aj * bj
+		}
+		if k < ln-1 {
+			upper[k] = aj1 * bj1
+		}
+		r0 := b[j].V()
+		if j == 1 {
+			r0 = r0.Subtract(out[0].V().Multiply(aj))
+		}
+		r1 := b[j+1].V()
+		if j+1 == m {
+			r1 = r1.Subtract(out[m].V().Multiply(bj1))
+		}
+		rhs[k] = r0.Multiply(bj).Add(r1.Multiply(aj1))
+	}
+
+	// Thomas algorithm
+	for k := 1; k < ln; k++ {
+		w := lower[k] / diag[k-1]
+		diag[k] -= w * upper[k-1]
+		rhs[k] = rhs[k].Subtract(rhs[k-1].Multiply(w))
+	}
+	q := rhs[ln-1].Multiply(1 / diag[ln-1])
+	out[ln] = q.Pt()
+	for k := ln - 2; k >= 0; k-- {
+		q = rhs[k].Subtract(q.Multiply(upper[k])).Multiply(1 / diag[k])
+		out[k+1] = q.Pt()
+	}
+	return out
+}
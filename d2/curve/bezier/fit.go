@@ -0,0 +1,202 @@
+package bezier
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/angle"
+	"github.com/adamcolton/geom/d2"
+)
+
+// FitCornerAngle is the change in direction at which Fit treats a point as a
+// corner. The curves on either side of a corner are fit independently.
+var FitCornerAngle = angle.Deg(60)
+
+// FitIterations is the number of times the parameterization is refined with
+// Newton's method before a range is split.
+var FitIterations = 4
+
+// Fit a chain of cubic Bezier curves to the points so that no point is further
+// than maxError from the chain. The curves meet with tangent continuity except
+// at corners, which are detected using FitCornerAngle.
+func Fit(pts []d2.Pt, maxError float64) []Bezier {
+	return FitCorners(pts, maxError, FitCornerAngle)
+}
+
+// FitCorners is the same as Fit but with an explicit corner angle.
+func FitCorners(pts []d2.Pt, maxError float64, corner angle.Rad) []Bezier {
+	// https://github.com/erich666/GraphicsGems/blob/master/gems/FitCurves.c
+	pts = dedupe(pts)
+	if len(pts) < 2 {
+		return nil
+	}
+	f := &fitter{
+		maxErr: maxError,
+	}
+	cos := math.Cos(float64(corner))
+	start := 0
+	for i := 1; i < len(pts)-1; i++ {
+		v0, v1 := unit(pts[i].Subtract(pts[i-1])), unit(pts[i+1].Subtract(pts[i]))
+		if v0.Dot(v1) < cos {
+			f.fitRange(pts[start : i+1])
+			start = i
+		}
+	}
+	f.fitRange(pts[start:])
+	return f.out
+}
+
+func dedupe(pts []d2.Pt) []d2.Pt {
+	out := make([]d2.Pt, 0, len(pts))
+	for i, pt := range pts {
+		if i == 0 || pt != pts[i-1] {
+			out = append(out, pt)
+		}
+	}
+	return out
+}
+
+func unit(v d2.V) d2.V {
+	m := v.Mag()
+	if m == 0 {
+		return v
+	}
+	return v.Multiply(1 / m)
+}
+
+type fitter struct {
+	maxErr float64
+	out    []Bezier
+}
+
+func (f *fitter) fitRange(pts []d2.Pt) {
+	ln := len(pts)
+	t0 := unit(pts[1].Subtract(pts[0]))
+	t1 := unit(pts[ln-2].Subtract(pts[ln-1]))
+	f.fit(pts, t0, t1)
+}
+
+// fit a single cubic to pts with the given end tangents. t0 points out of the
+// start and t1 points out of the end, back along the curve.
+func (f *fitter) fit(pts []d2.Pt, t0, t1 d2.V) {
+	ln := len(pts)
+	if ln == 2 {
+		d := pts[0].Distance(pts[1]) / 3
+		f.out = append(f.out, Bezier{
+			pts[0],
+			pts[0].Add(t0.Multiply(d)),
+			pts[1].Add(t1.Multiply(d)),
+			pts[1],
+		})
+		return
+	}
+
+	u := chordLength(pts)
+	b := generate(pts, u, t0, t1)
+	e, split := maxError(pts, b, u)
+	if e < f.maxErr {
+		f.out = append(f.out, b)
+		return
+	}
+	if e < f.maxErr*4 {
+		for i := 0; i < FitIterations; i++ {
+			u = reparameterize(pts, b, u)
+			b = generate(pts, u, t0, t1)
+			e, split = maxError(pts, b, u)
+			if e < f.maxErr {
+				f.out = append(f.out, b)
+				return
+			}
+		}
+	}
+
+	center := unit(pts[split-1].Subtract(pts[split+1]))
+	f.fit(pts[:split+1], t0, center)
+	f.fit(pts[split:], center.Multiply(-1), t1)
+}
+
+// chordLength assigns a parametric value to each point proportional to the
+// distance along the polyline.
+func chordLength(pts []d2.Pt) []float64 {
+	u := make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		u[i] = u[i-1] + pts[i].Distance(pts[i-1])
+	}
+	l := u[len(u)-1]
+	for i := range u {
+		u[i] /= l
+	}
+	return u
+}
+
+// generate finds the least squares cubic with the given end points and tangent
+// directions.
+func generate(pts []d2.Pt, u []float64, t0, t1 d2.V) Bezier {
+	ln := len(pts)
+	p0, p3 := pts[0], pts[ln-1]
+	var c00, c01, c11, x0, x1 float64
+	for i, t := range u {
+		ti := 1 - t
+		b0 := ti * ti * ti
+		b1 := 3 * t * ti * ti
+		b2 := 3 * t * t * ti
+		b3 := t * t * t
+		a0, a1 := t0.Multiply(b1), t1.Multiply(b2)
+		c00 += a0.Dot(a0)
+		c01 += a0.Dot(a1)
+		c11 += a1.Dot(a1)
+		tmp := pts[i].Subtract(p0.Multiply(b0 + b1).Add(p3.Multiply(b2 + b3).V()))
+		x0 += a0.Dot(tmp)
+		x1 += a1.Dot(tmp)
+	}
+
+	det := c00*c11 - c01*c01
+	var al, ar float64
+	if det != 0 {
+		al = (x0*c11 - c01*x1) / det
+		ar = (c00*x1 - c01*x0) / det
+	}
+
+	// If the least squares solution is degenerate, fall back on the Wu/Barsky
+	// heuristic.
+	segLength := p0.Distance(p3)
+	eps := 1e-6 * segLength
+	if al < eps || ar < eps {
+		al = segLength / 3
+		ar = al
+	}
+	return Bezier{p0, p0.Add(t0.Multiply(al)), p3.Add(t1.Multiply(ar)), p3}
+}
+
+// maxError returns the largest distance from a point to the curve and the index
+// of that point.
+func maxError(pts []d2.Pt, b Bezier, u []float64) (float64, int) {
+	max, split := 0.0, len(pts)/2
+	for i := 1; i < len(pts)-1; i++ {
+		d := b.Pt1(u[i]).Subtract(pts[i]).Mag2()
+		if d >= max {
+			max, split = d, i
+		}
+	}
+	return math.Sqrt(max), split
+}
+
+// reparameterize improves the parametric value assigned to each point with a
+// Newton step minimizing the distance to the curve.
+func reparameterize(pts []d2.Pt, b Bezier, u []float64) []float64 {
+	d1 := b.Tangent()
+	dd := d1.Bezier.Tangent()
+	out := make([]float64, len(u))
+	for i, t := range u {
+		q := b.Pt1(t).Subtract(pts[i])
+		q1 := d1.V1(t)
+		q2 := dd.V1(t)
+		num := q.Dot(q1)
+		den := q1.Dot(q1) + q.Dot(q2)
+		if den == 0 {
+			out[i] = t
+			continue
+		}
+		out[i] = t - num/den
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package bezier
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/stretchr/testify/assert"
+)
+
+func closestDistance(bs []Bezier, pt d2.Pt) float64 {
+	d := math.Inf(1)
+	for _, b := range bs {
+		for t := 0.0; t <= 1.0; t += 0.001 {
+			d = math.Min(d, b.Pt1(t).Distance(pt))
+		}
+	}
+	return d
+}
+
+func TestFit(t *testing.T) {
+	pts := make([]d2.Pt, 100)
+	for i := range pts {
+		x := float64(i) / 10
+		pts[i] = d2.Pt{x, math.Sin(x)}
+	}
+	bs := Fit(pts, 0.01)
+	assert.True(t, len(bs) > 0 && len(bs) < 10)
+	assert.Equal(t, pts[0], bs[0][0])
+	assert.Equal(t, pts[99], bs[len(bs)-1][3])
+	for _, pt := range pts {
+		assert.True(t, closestDistance(bs, pt) < 0.01)
+	}
+
+	// Tangent continuity between curves
+	for i := 1; i < len(bs); i++ {
+		assert.Equal(t, bs[i-1][3], bs[i][0])
+		v0, v1 := bs[i-1].V1(1), bs[i].V1(0)
+		assert.InDelta(t, 0, v0.Cross(v1)/(v0.Mag()*v1.Mag()), 1e-9)
+		assert.True(t, v0.Dot(v1) > 0)
+	}
+}
+
+func TestFitCorner(t *testing.T) {
+	// An L shape should be split at the corner.
+	var pts []d2.Pt
+	for i := 0; i <= 10; i++ {
+		pts = append(pts, d2.Pt{0, 1 - float64(i)/10})
+	}
+	for i := 1; i <= 10; i++ {
+		pts = append(pts, d2.Pt{float64(i) / 10, 0})
+	}
+	bs := Fit(pts, 0.001)
+	assert.Len(t, bs, 2)
+	assert.Equal(t, d2.Pt{0, 0}, bs[0][3])
+	for _, pt := range pts {
+		assert.True(t, closestDistance(bs, pt) < 0.001)
+	}
+}
+
+func TestFitEdgeCases(t *testing.T) {
+	assert.Nil(t, Fit(nil, 1))
+	assert.Nil(t, Fit([]d2.Pt{{1, 1}, {1, 1}}, 1))
+	bs := Fit([]d2.Pt{{0, 0}, {3, 0}}, 1)
+	assert.Equal(t, []Bezier{{{0, 0}, {1, 0}, {2, 0}, {3, 0}}}, bs)
+}
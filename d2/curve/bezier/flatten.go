@@ -0,0 +1,62 @@
+package bezier
+
+import (
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// FlattenMaxDepth limits the number of subdivisions used by Flatten.
+var FlattenMaxDepth = 20
+
+// Flatten converts the curve to line.Segments so that no point on the curve is
+// further than maxDeviation from the segments. Because the curve lies within
+// the convex hull of its control points, the curve is flat enough when every
+// control point is within maxDeviation of the chord.
+func (b Bezier) Flatten(maxDeviation float64) line.Segments {
+	if len(b) == 0 {
+		return nil
+	}
+	out := line.Segments{b[0]}
+	buf := b.newBuf(nil, nil)
+	return b.flatten(maxDeviation, 0, buf, out)
+}
+
+func (b Bezier) flatten(max float64, depth int, buf buf, out line.Segments) line.Segments {
+	ln := len(b) - 1
+	if depth >= FlattenMaxDepth || b.flat(max) {
+		return append(out, b[ln])
+	}
+	buf.Bezier = b
+	l := buf.segment(0, 0.5).Bezier
+	buf.Bezier = b
+	r := buf.segment(0.5, 1).Bezier
+	depth++
+	out = l.flatten(max, depth, buf, out)
+	return r.flatten(max, depth, buf, out)
+}
+
+func (b Bezier) flat(max float64) bool {
+	ln := len(b) - 1
+	p0, p1 := b[0], b[ln]
+	l := line.New(p0, p1)
+	m2 := l.D.Mag2()
+	max2 := max * max
+	for _, pt := range b[1:ln] {
+		var d d2.V
+		if m2 == 0 {
+			d = pt.Subtract(p0)
+		} else {
+			t := l.D.Dot(pt.Subtract(p0)) / m2
+			if t < 0 {
+				t = 0
+			} else if t > 1 {
+				t = 1
+			}
+			d = pt.Subtract(l.Pt1(t))
+		}
+		if d.Mag2() > max2 {
+			return false
+		}
+	}
+	return true
+}
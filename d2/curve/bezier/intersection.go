@@ -1,7 +1,12 @@
 package bezier
 
 import (
+	"math"
+
+	"github.com/adamcolton/geom/calc/cmpr"
 	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/intersect"
 	"github.com/adamcolton/geom/d2/curve/line"
 	"github.com/adamcolton/geom/d2/curve/poly"
 )
@@ -99,3 +104,221 @@ func (b buf) segment(start, end float64) buf {
 		Bezier: out,
 	}
 }
+
+// MaxDepth limits the recursion when subdividing curves to find intersections.
+var MaxDepth = 40
+
+// FlatTolerance is relative to the size of the curves passed to
+// Intersections. Once both pieces are within this distance of their chords,
+// they are intersected as lines.
+var FlatTolerance = 1e-6
+
+// Intersections finds the points where b intersects b2 by recursive
+// subdivision. Each returned pair holds the parametric value on b followed by
+// the parametric value on b2. The buffer follows the same length rules as
+// line.Intersector. Sections where the curves overlap are not reported.
+func (b Bezier) Intersections(b2 Bezier, buf [][2]float64) [][2]float64 {
+	min, max := d2.MinMax(append(append([]d2.Pt(nil), b...), b2...)...)
+	x := &bezierIntersector{
+		a:    b,
+		b:    b2,
+		flat: max.Subtract(min).Mag() * FlatTolerance,
+		max:  len(buf),
+		out:  buf[:0],
+	}
+	x.find(span{b, 0, 1}, span{b2, 0, 1}, 0)
+	return x.out
+}
+
+// EllipseArcIntersections finds the points where b intersects the EllipseArc.
+// Each returned pair holds the parametric value on b followed by the
+// parametric value on e. The buffer follows the same length rules as
+// line.Intersector.
+func (b Bezier) EllipseArcIntersections(e *ellipsearc.EllipseArc, buf [][2]float64) [][2]float64 {
+	// Transform b into the space where e is the unit circle. The transformed
+	// curve is still a Bezier and the intersections are the roots of
+	// |B(t)|^2 - 1.
+	c := e.Centroid()
+	M, m := e.Axis()
+	_, s, cs := e.Angle()
+	u := make(Bezier, len(b))
+	for i, pt := range b {
+		v := pt.Subtract(c)
+		u[i] = d2.Pt{
+			X: (v.X*cs + v.Y*s) / M,
+			Y: (v.Y*cs - v.X*s) / m,
+		}
+	}
+
+	x := &ellipseIntersector{
+		u:   u,
+		b:   b,
+		e:   e,
+		max: len(buf),
+		out: buf[:0],
+	}
+	x.find(span{u, 0, 1}, 0)
+	return x.out
+}
+
+type span struct {
+	Bezier
+	t0, t1 float64
+}
+
+func (s span) split() (span, span) {
+	m := (s.t0 + s.t1) / 2
+	return span{s.Segment(0, 0.5), s.t0, m}, span{s.Segment(0.5, 1), m, s.t1}
+}
+
+func (s span) small() bool {
+	min, max := d2.MinMax(s.Bezier...)
+	return intersect.Tolerance.Zero(max.Subtract(min).Mag())
+}
+
+type bezierIntersector struct {
+	a, b Bezier
+	flat float64
+	max  int
+	out  [][2]float64
+}
+
+func (x *bezierIntersector) done() bool {
+	return x.max > 0 && len(x.out) >= x.max
+}
+
+func (x *bezierIntersector) find(a, b span, depth int) {
+	if x.done() {
+		return
+	}
+	amin, amax := d2.MinMax(a.Bezier...)
+	bmin, bmax := d2.MinMax(b.Bezier...)
+	if amax.X < bmin.X || bmax.X < amin.X || amax.Y < bmin.Y || bmax.Y < amin.Y {
+		return
+	}
+	if depth >= MaxDepth || (a.small() && b.small()) {
+		x.newton((a.t0+a.t1)/2, (b.t0+b.t1)/2)
+		return
+	}
+	if a.flat(x.flat) && b.flat(x.flat) {
+		x.chords(a, b)
+		return
+	}
+	a0, a1 := a.split()
+	b0, b1 := b.split()
+	depth++
+	x.find(a0, b0, depth)
+	x.find(a0, b1, depth)
+	x.find(a1, b0, depth)
+	x.find(a1, b1, depth)
+}
+
+func (x *bezierIntersector) newton(t0, t1 float64) {
+	t0, t1, ok := intersect.Newton(x.a, x.b, t0, t1)
+	if ok && t0 >= 0 && t0 <= 1 && t1 >= 0 && t1 <= 1 {
+		x.out = intersect.Append(x.out, t0, t1)
+	}
+}
+
+// chords intersects two flat spans as lines. This also stops the subdivision
+// where the curves overlap, which would otherwise grow exponentially.
+func (x *bezierIntersector) chords(a, b span) {
+	p, q := a.Bezier[0], b.Bezier[0]
+	da := a.Bezier[len(a.Bezier)-1].Subtract(p)
+	db := b.Bezier[len(b.Bezier)-1].Subtract(q)
+	v := q.Subtract(p)
+	c := da.Cross(db)
+	if math.Abs(c) <= FlatTolerance*da.Mag()*db.Mag() {
+		// parallel, either apart or overlapping
+		return
+	}
+	sa, sb := v.Cross(db)/c, v.Cross(da)/c
+	const slack = 1e-6
+	if sa < -slack || sa > 1+slack || sb < -slack || sb > 1+slack {
+		return
+	}
+	x.newton(a.t0+sa*(a.t1-a.t0), b.t0+sb*(b.t1-b.t0))
+}
+
+type ellipseIntersector struct {
+	u, b Bezier
+	e    *ellipsearc.EllipseArc
+	max  int
+	out  [][2]float64
+}
+
+func (x *ellipseIntersector) find(s span, depth int) {
+	if x.max > 0 && len(x.out) >= x.max {
+		return
+	}
+	// If every control point is inside the unit circle then so is the convex
+	// hull.
+	inside := true
+	for _, pt := range s.Bezier {
+		if pt.Mag2() >= 1 {
+			inside = false
+			break
+		}
+	}
+	if inside {
+		return
+	}
+	// If the bounding box of the control points is outside the unit circle then
+	// so is the hull.
+	min, max := d2.MinMax(s.Bezier...)
+	closest := d2.Pt{
+		X: math.Max(min.X, math.Min(0, max.X)),
+		Y: math.Max(min.Y, math.Min(0, max.Y)),
+	}
+	if closest.Mag2() > 1 {
+		return
+	}
+	if depth < MaxDepth && !s.small() {
+		s0, s1 := s.split()
+		depth++
+		x.find(s0, depth)
+		x.find(s1, depth)
+		return
+	}
+
+	// Newton's method on f(t) = |B(t)|^2 - 1
+	t := (s.t0 + s.t1) / 2
+	tan := x.u.Tangent()
+	for i := 0; i < 50; i++ {
+		p := x.u.Pt1(t).V()
+		f := p.Mag2() - 1
+		if intersect.Tolerance.Zero(f) {
+			break
+		}
+		df := 2 * p.Dot(tan.V1(t))
+		if df == 0 {
+			break
+		}
+		t -= f / df
+	}
+	p := x.u.Pt1(t)
+	if t < 0 || t > 1 || !cmpr.Tolerance(1e-6).Zero(p.Mag2()-1) {
+		return
+	}
+	at := p.Angle().Rad() - x.e.Start
+	if x.e.Length < 0 {
+		at = -at
+	}
+	at = math.Mod(at, 2*math.Pi)
+	if at < 0 {
+		at += 2 * math.Pi
+	}
+	et := at / math.Abs(x.e.Length)
+	if et > 1 {
+		if !cmpr.Tolerance(1e-9).Equal(at, 2*math.Pi) {
+			return
+		}
+		et = 0
+	}
+	x.out = intersect.Append(x.out, t, et)
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (b Bezier) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return b.Segment(t0, t1)
+}
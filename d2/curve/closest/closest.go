@@ -0,0 +1,81 @@
+// Package closest finds the point on a curve nearest to a given point.
+package closest
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+)
+
+// Nearest returns the parametric value in the range [0,1] of the point on the
+// curve closest to pt and the distance to that point.
+type Nearest interface {
+	Nearest(pt d2.Pt) (t0, d float64)
+}
+
+var (
+	// Steps is the number of points the curve is sampled at to find starting
+	// values for Newton's method.
+	Steps = 50
+	// Iterations limits the number of Newton steps taken from each starting
+	// value.
+	Iterations = 20
+)
+
+// Pt1 finds the parametric value in the range [0,1] of the point on any curve
+// closest to pt and the distance to that point. If the curve fulfills Nearest,
+// that will be used, otherwise Numeric is used.
+func Pt1(c d2.Pt1, pt d2.Pt) (float64, float64) {
+	if n, ok := c.(Nearest); ok {
+		return n.Nearest(pt)
+	}
+	return Numeric(c, pt)
+}
+
+// Numeric finds the closest point by sampling the curve and refining each local
+// minimum with Newton's method. It does not check for Nearest so that it can
+// be used to implement Nearest.
+func Numeric(c d2.Pt1, pt d2.Pt) (float64, float64) {
+	steps := Steps
+	if steps < 2 {
+		steps = 2
+	}
+	ds := make([]float64, steps+1)
+	for i := range ds {
+		ds[i] = c.Pt1(float64(i) / float64(steps)).Distance(pt)
+	}
+
+	v1, v2 := d2.GetV1(c), d2.GetV2(c)
+	bestT, bestD := 0.0, math.Inf(1)
+	for i, d := range ds {
+		if (i > 0 && ds[i-1] < d) || (i < steps && ds[i+1] < d) {
+			continue
+		}
+		lo, hi := float64(i-1)/float64(steps), float64(i+1)/float64(steps)
+		t := newton(c, v1, v2, pt, float64(i)/float64(steps), math.Max(lo, 0), math.Min(hi, 1))
+		if d = c.Pt1(t).Distance(pt); d < bestD {
+			bestT, bestD = t, d
+		}
+	}
+	return bestT, bestD
+}
+
+// newton minimizes the distance from c to pt by finding where
+// (c(t)-pt)·c'(t) = 0. The value is kept in the range [lo, hi].
+func newton(c d2.Pt1, v1 d2.V1, v2 d2.V2, pt d2.Pt, t, lo, hi float64) float64 {
+	for i := 0; i < Iterations; i++ {
+		q := c.Pt1(t).Subtract(pt)
+		d1 := v1.V1(t)
+		f := q.Dot(d1)
+		df := d1.Dot(d1) + q.Dot(v2.V2(t))
+		if df <= 0 {
+			return t
+		}
+		nt := math.Max(lo, math.Min(hi, t-f/df))
+		if math.Abs(nt-t) < 1e-14 {
+			return nt
+		}
+		t = nt
+	}
+	return t
+}
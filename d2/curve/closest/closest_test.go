@@ -0,0 +1,55 @@
+package closest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/curve/poly"
+	"github.com/adamcolton/geom/geomtest"
+)
+
+type pt1 struct {
+	c d2.Pt1
+}
+
+func (p pt1) Pt1(t0 float64) d2.Pt {
+	return p.c.Pt1(t0)
+}
+
+func TestNumeric(t *testing.T) {
+	b := poly.NewBezier([]d2.Pt{{0, 0}, {0, 10}, {10, -5}, {10, 5}})
+	for _, pt := range []d2.Pt{{5, 5}, {-1, -1}, {11, 7}, {3, 1}, {8, -2}} {
+		et, ed := b.Nearest(pt)
+		nt, nd := Pt1(pt1{b}, pt)
+		geomtest.EqualInDelta(t, et, nt, 1e-6)
+		geomtest.EqualInDelta(t, ed, nd, 1e-6)
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := circle{}
+	tt, d := Pt1(c, d2.Pt{0, 3})
+	geomtest.EqualInDelta(t, 0.25, tt, 1e-6)
+	geomtest.Equal(t, 2.0, d)
+
+	// both 0 and 1 are the closest point
+	tt, d = Pt1(c, d2.Pt{0.5, 0})
+	geomtest.Equal(t, d2.Pt{1, 0}, c.Pt1(tt))
+	geomtest.Equal(t, 0.5, d)
+}
+
+func TestPt1Nearest(t *testing.T) {
+	l := line.New(d2.Pt{0, 0}, d2.Pt{2, 0})
+	tt, d := Pt1(l, d2.Pt{3, 1})
+	geomtest.Equal(t, 1.0, tt)
+	geomtest.Equal(t, math.Sqrt2, d)
+}
+
+type circle struct{}
+
+func (circle) Pt1(t0 float64) d2.Pt {
+	s, c := math.Sincos(t0 * 2 * math.Pi)
+	return d2.Pt{c, s}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/adamcolton/geom/angle"
 	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/closest"
 	"github.com/adamcolton/geom/d2/curve/line"
 )
 
@@ -38,6 +39,26 @@ func New(pt1, pt2 d2.Pt, r float64) *EllipseArc {
 	return e
 }
 
+// NewAxis returns a full EllipseArc with the given center, semi-major axis,
+// semi-minor axis and rotation of the major axis. If minor is greater than
+// major they are swapped and the rotation is adjusted by a quarter turn so the
+// parametric angle still proceeds from the major axis.
+func NewAxis(center d2.Pt, major, minor float64, a angle.Rad) *EllipseArc {
+	if minor > major {
+		major, minor = minor, major
+		a += math.Pi / 2
+	}
+	e := &EllipseArc{
+		c:      center,
+		Length: math.Pi * 2,
+		sMa:    major,
+		sma:    minor,
+		a:      a,
+	}
+	e.as, e.ac = a.Sincos()
+	return e
+}
+
 // Pt1 returns the float64 vector at t.
 func (e *EllipseArc) Pt1(t float64) d2.Pt {
 	return e.c.Add(e.ByAngle((e.Length)*t + e.Start))
@@ -46,7 +67,19 @@ func (e *EllipseArc) Pt1(t float64) d2.Pt {
 // V1 returns a tangent vector at t.
 func (e *EllipseArc) V1(t float64) d2.V {
 	t = (e.Length)*t + e.Start
-	return e.ByAngle(t + math.Pi/2).V().Multiply(2 * math.Pi)
+	return e.ByAngle(t + math.Pi/2).V().Multiply(e.Length)
+}
+
+// V2 returns the second derivative at t.
+func (e *EllipseArc) V2(t float64) d2.V {
+	t = (e.Length)*t + e.Start
+	return e.ByAngle(t).Multiply(-e.Length * e.Length)
+}
+
+// Nearest returns the parametric value of the point on the arc closest to pt
+// and the distance to that point.
+func (e *EllipseArc) Nearest(pt d2.Pt) (float64, float64) {
+	return closest.Numeric(e, pt)
 }
 
 // ByAngle returns the vector at the given angle relative to the center
@@ -184,3 +217,67 @@ func (e *EllipseArc) ABC(y float64) (float64, float64, float64) {
 		2 * y * c * s * (v2 - h2),
 		y*y*(v2*s2+h2*c2) - h2*v2
 }
+
+// Segment returns an EllipseArc describing the range [t0, t1] of the arc.
+func (e *EllipseArc) Segment(t0, t1 float64) *EllipseArc {
+	out := *e
+	out.Start = e.Start + e.Length*t0
+	out.Length = e.Length * (t1 - t0)
+	return &out
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (e *EllipseArc) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return e.Segment(t0, t1)
+}
+
+// Reverse returns an EllipseArc tracing the arc in the opposite direction.
+func (e *EllipseArc) Reverse() *EllipseArc {
+	return e.Segment(1, 0)
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (e *EllipseArc) Pt1Reverse() d2.Pt1 {
+	return e.Reverse()
+}
+
+// T returns the EllipseArc with the transform applied. The image of an ellipse
+// under an affine transform is always an ellipse, so this is exact. The linear
+// part of the transform combined with the axes and rotation is decomposed into
+// rotation * scale * rotation to find the new axes.
+func (e *EllipseArc) T(t *d2.T) *EllipseArc {
+	// Q = A * R(a) * diag(sMa, sma)
+	col0 := t.Linear(d2.V{e.sMa * e.ac, e.sMa * e.as})
+	col1 := t.Linear(d2.V{-e.sma * e.as, e.sma * e.ac})
+	q00, q10, q01, q11 := col0.X, col0.Y, col1.X, col1.Y
+
+	// Closed form singular value decomposition of a 2x2 matrix.
+	E, F := (q00+q11)/2, (q00-q11)/2
+	G, H := (q10+q01)/2, (q10-q01)/2
+	q, r := math.Sqrt(E*E+H*H), math.Sqrt(F*F+G*G)
+	sx, sy := q+r, q-r
+	a1, a2 := math.Atan2(G, F), math.Atan2(H, E)
+	theta, phi := (a2-a1)/2, (a2+a1)/2
+
+	// Q = R(phi) * diag(sx, sy) * R(theta). A negative sy is a reflection which
+	// reverses the direction of the parametric angle.
+	s := 1.0
+	if sy < 0 {
+		s, sy = -1, -sy
+	}
+	out := &EllipseArc{
+		c:      t.Pt(e.c),
+		sMa:    sx,
+		sma:    sy,
+		a:      angle.Rad(phi),
+		Start:  s * (e.Start + theta),
+		Length: s * e.Length,
+	}
+	out.as, out.ac = out.a.Sincos()
+	return out
+}
+
+// Pt1T fulfills d2.Transformer.
+func (e *EllipseArc) Pt1T(t *d2.T) d2.Pt1 {
+	return e.T(t)
+}
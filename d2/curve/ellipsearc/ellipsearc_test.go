@@ -4,6 +4,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/adamcolton/geom/angle"
 	"github.com/adamcolton/geom/d2"
 	"github.com/adamcolton/geom/d2/curve/line"
 	"github.com/adamcolton/geom/d2/shape/polygon"
@@ -163,3 +164,98 @@ func TestLineIntersections(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAxis(t *testing.T) {
+	e := NewAxis(d2.Pt{1, 2}, 3, 1, angle.Deg(90))
+	geomtest.Equal(t, d2.Pt{1, 5}, e.Pt1(0))
+	geomtest.Equal(t, d2.Pt{0, 2}, e.Pt1(0.25))
+	M, m := e.Axis()
+	geomtest.Equal(t, 3.0, M)
+	geomtest.Equal(t, 1.0, m)
+
+	swapped := NewAxis(d2.Pt{1, 2}, 1, 3, 0)
+	M, m = swapped.Axis()
+	geomtest.Equal(t, 3.0, M)
+	geomtest.Equal(t, 1.0, m)
+	geomtest.Equal(t, d2.Pt{1, 5}, swapped.Pt1(0))
+}
+
+func TestV1Arc(t *testing.T) {
+	// The tangent scales with the length of the arc, not a full turn. This arc
+	// proceeds clockwise over 2 radians so V1 points the other way and is
+	// shorter than for a full ellipse.
+	e := New(d2.Pt{0, 0}, d2.Pt{2, 0}, 1)
+	e.Start, e.Length = 1, -2
+	geomtest.Equal(t, d2.AssertV1{}, e)
+}
+
+func TestV2(t *testing.T) {
+	e := NewAxis(d2.Pt{1, 2}, 3, 2, angle.Deg(30))
+	geomtest.Equal(t, d2.AssertV2{}, e)
+	e.Start, e.Length = 1, -2
+	geomtest.Equal(t, d2.AssertV2{}, e)
+
+	c := New(d2.Pt{0, 0}, d2.Pt{0, 0}, 2)
+	for i := 0.0; i < 1.0; i += 0.1 {
+		geomtest.Equal(t, 0.5, d2.Curvature(c, i))
+		center, r := d2.OsculatingCircle(c, i)
+		geomtest.Equal(t, d2.Pt{0, 0}, center)
+		geomtest.Equal(t, 2.0, r)
+	}
+
+	// at the end of the major axis the radius of curvature is b^2/a
+	_, r := d2.OsculatingCircle(e, 0.5)
+	geomtest.Equal(t, 4.0/3.0, r)
+}
+
+func TestNearest(t *testing.T) {
+	e := NewAxis(d2.Pt{1, 1}, 3, 1, 0)
+	tt, d := e.Nearest(d2.Pt{1, 3})
+	geomtest.Equal(t, 0.25, tt)
+	geomtest.Equal(t, 1.0, d)
+
+	tt, d = e.Nearest(d2.Pt{5, 1})
+	assert.True(t, tt < 1e-6 || tt > 1-1e-6)
+	geomtest.Equal(t, 1.0, d)
+
+	e.Start, e.Length = 0, math.Pi/2
+	tt, d = e.Nearest(d2.Pt{-5, 1})
+	geomtest.Equal(t, 1.0, tt)
+	geomtest.Equal(t, d2.Pt{1, 2}.Distance(d2.Pt{-5, 1}), d)
+}
+
+func TestSegmentReverse(t *testing.T) {
+	e := NewAxis(d2.Pt{1, 2}, 3, 2, angle.Deg(30))
+	e.Start, e.Length = 1, 4
+	s := e.Segment(0.25, 0.75)
+	r := e.Reverse()
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, e.Pt1(0.25+i*0.5), s.Pt1(i))
+		geomtest.Equal(t, e.Pt1(1-i), r.Pt1(i))
+	}
+	geomtest.Equal(t, d2.AssertV1{}, s)
+	geomtest.Equal(t, d2.AssertV1{}, r)
+}
+
+func TestT(t *testing.T) {
+	e := NewAxis(d2.Pt{1, 2}, 3, 1, angle.Deg(30))
+	e.Start, e.Length = 0.5, 2
+
+	tt := map[string]*d2.T{
+		"rotate":  d2.Rotate(angle.Deg(45)).T(),
+		"shear":   {{1, 0.5, 2}, {0, 1, -1}, {0, 0, 1}},
+		"reflect": {{-1, 0, 0}, {0, 1, 3}, {0, 0, 1}},
+		"stretch": {{1, 0, 0}, {0, 5, 0}, {0, 0, 1}},
+	}
+	for n, tc := range tt {
+		t.Run(n, func(t *testing.T) {
+			got := e.T(tc)
+			for i := 0.0; i <= 1.0; i += 0.125 {
+				geomtest.Equal(t, tc.Pt(e.Pt1(i)), got.Pt1(i))
+			}
+			M, m := got.Axis()
+			assert.True(t, M >= m)
+			geomtest.Equal(t, d2.AssertV1{}, got)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package flatten converts curves to line.Segments by adaptive subdivision.
+package flatten
+
+import (
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// Flattener can convert itself to line.Segments so that no point on the curve
+// is further than maxDeviation from the segments.
+type Flattener interface {
+	Flatten(maxDeviation float64) line.Segments
+}
+
+var (
+	// MinDepth is the number of times the range is always subdivided before
+	// testing flatness. Without it, features that fall between the samples of
+	// the flatness test can be missed.
+	MinDepth = 3
+	// MaxDepth limits the number of subdivisions.
+	MaxDepth = 20
+)
+
+// Pt1 flattens any curve over the range [0,1]. If the curve fulfills
+// Flattener, that will be used. Otherwise the range is recursively subdivided
+// until the curve at the midpoint and quarter points of each range is within
+// maxDeviation of the chord.
+func Pt1(c d2.Pt1, maxDeviation float64) line.Segments {
+	switch f := c.(type) {
+	case Flattener:
+		return f.Flatten(maxDeviation)
+	case line.Line:
+		return line.Segments{f.Pt1(0), f.Pt1(1)}
+	case line.Segments:
+		return f
+	}
+	start := c.Pt1(0)
+	s := &subdivider{
+		c:   c,
+		max: maxDeviation,
+		out: line.Segments{start},
+	}
+	s.subdivide(0, 1, start, c.Pt1(1), 0)
+	return s.out
+}
+
+type subdivider struct {
+	c   d2.Pt1
+	max float64
+	out line.Segments
+}
+
+func (s *subdivider) subdivide(t0, t1 float64, p0, p1 d2.Pt, depth int) {
+	m := (t0 + t1) / 2
+	pm := s.c.Pt1(m)
+	if depth >= MaxDepth || (depth >= MinDepth &&
+		SegmentDistance(p0, p1, pm) <= s.max &&
+		SegmentDistance(p0, p1, s.c.Pt1((t0+m)/2)) <= s.max &&
+		SegmentDistance(p0, p1, s.c.Pt1((m+t1)/2)) <= s.max) {
+		s.out = append(s.out, p1)
+		return
+	}
+	depth++
+	s.subdivide(t0, m, p0, pm, depth)
+	s.subdivide(m, t1, pm, p1, depth)
+}
+
+// SegmentDistance returns the distance from pt to the line segment from p0 to
+// p1.
+func SegmentDistance(p0, p1, pt d2.Pt) float64 {
+	l := line.New(p0, p1)
+	m2 := l.D.Mag2()
+	if m2 == 0 {
+		return pt.Distance(p0)
+	}
+	t := l.D.Dot(pt.Subtract(p0)) / m2
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return l.Pt1(t).Distance(pt)
+}
@@ -0,0 +1,67 @@
+package flatten
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// maxDistance samples c densely and returns the furthest any sample is from
+// the segments.
+func maxDistance(c d2.Pt1, s line.Segments) float64 {
+	var max float64
+	for t := 0.0; t <= 1.0; t += 0.001 {
+		pt := c.Pt1(t)
+		d := math.Inf(1)
+		for i := range s[1:] {
+			d = math.Min(d, SegmentDistance(s[i], s[i+1], pt))
+		}
+		max = math.Max(max, d)
+	}
+	return max
+}
+
+func TestPt1(t *testing.T) {
+	e := ellipsearc.New(d2.Pt{0, 0}, d2.Pt{4, 0}, 1)
+	for _, tol := range []float64{0.1, 0.01, 0.001} {
+		s := Pt1(e, tol)
+		geomtest.Equal(t, e.Pt1(0), s[0])
+		geomtest.Equal(t, e.Pt1(1), s[len(s)-1])
+		assert.True(t, maxDistance(e, s) <= tol)
+	}
+
+	// The flat ends of an elongated ellipse need fewer points than the tight
+	// bends.
+	s := Pt1(e, 0.01)
+	var flat, bend int
+	for _, pt := range s {
+		if math.Abs(pt.X-2) < 1 {
+			flat++
+		} else {
+			bend++
+		}
+	}
+	assert.True(t, bend > flat)
+}
+
+func TestFlattener(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+	s := Pt1(b, 0.01)
+	assert.Equal(t, b.Flatten(0.01), s)
+	assert.True(t, maxDistance(b, s) <= 0.01)
+
+	l := line.New(d2.Pt{1, 2}, d2.Pt{3, 4})
+	assert.Equal(t, line.Segments{{1, 2}, {3, 4}}, Pt1(l, 0.1))
+}
+
+func TestSegmentDistance(t *testing.T) {
+	geomtest.Equal(t, 1.0, SegmentDistance(d2.Pt{0, 0}, d2.Pt{2, 0}, d2.Pt{1, 1}))
+	geomtest.Equal(t, 1.0, SegmentDistance(d2.Pt{0, 0}, d2.Pt{2, 0}, d2.Pt{3, 0}))
+	geomtest.Equal(t, 5.0, SegmentDistance(d2.Pt{0, 0}, d2.Pt{0, 0}, d2.Pt{3, 4}))
+}
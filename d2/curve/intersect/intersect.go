@@ -0,0 +1,104 @@
+// Package intersect finds the intersections between arbitrary curves.
+package intersect
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/calc/cmpr"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// Tolerance is the distance at which two points are considered coincident
+// when refining and deduplicating intersections.
+var Tolerance = cmpr.Tolerance(1e-9)
+
+// Steps is the number of line segments each curve is broken into by Curves if
+// steps is 0.
+var Steps = 100
+
+// Newton refines an approximate intersection of curves a and b starting from
+// t0 on a and t1 on b. It returns the refined values and true if the points
+// converged to within Tolerance.
+func Newton(a, b d2.Pt1, t0, t1 float64) (float64, float64, bool) {
+	av, bv := d2.GetV1(a), d2.GetV1(b)
+	for i := 0; i < 50; i++ {
+		f := a.Pt1(t0).Subtract(b.Pt1(t1))
+		if Tolerance.Zero(f.X) && Tolerance.Zero(f.Y) {
+			return t0, t1, true
+		}
+		// Solve [a'(t0), -b'(t1)] * [d0, d1] = -f
+		da, db := av.V1(t0), bv.V1(t1).Multiply(-1)
+		det := da.Cross(db)
+		if det == 0 {
+			break
+		}
+		t0 -= f.Cross(db) / det
+		t1 -= da.Cross(f) / det
+		if math.IsNaN(t0) || math.IsNaN(t1) {
+			break
+		}
+	}
+	f := a.Pt1(t0).Subtract(b.Pt1(t1))
+	return t0, t1, Tolerance.Zero(f.X) && Tolerance.Zero(f.Y)
+}
+
+// Append adds the intersection to buf if an equivalent intersection is not
+// already present.
+func Append(buf [][2]float64, t0, t1 float64) [][2]float64 {
+	const dup = cmpr.Tolerance(1e-7)
+	for _, ts := range buf {
+		if dup.Equal(ts[0], t0) && dup.Equal(ts[1], t1) {
+			return buf
+		}
+	}
+	return append(buf, [2]float64{t0, t1})
+}
+
+// Curves finds the intersections of a and b over the range [0,1]. Each curve
+// is approximated by steps line segments, the intersections of those are
+// then refined using Newton's method. If steps is 0, Steps is used. The first
+// value of each returned pair is relative to a and the second is relative to
+// b. The buffer follows the same length rules as line.Intersector.
+func Curves(a, b d2.Pt1, steps int, buf [][2]float64) [][2]float64 {
+	if steps <= 0 {
+		steps = Steps
+	}
+	max := len(buf)
+	buf = buf[:0]
+	as, bs := sample(a, steps), sample(b, steps)
+	fs := float64(steps)
+	for i, sa := range as {
+		for j, sb := range bs {
+			t0, t1, ok := line.DefaultRange.Check(sa.Intersection(sb))
+			if !ok && (i == steps-1 || j == steps-1) {
+				// include the end of the final segments
+				t0, t1, ok = sa.Intersection(sb)
+				ok = ok && t0 >= 0 && t0 <= 1 && t1 >= 0 && t1 <= 1
+			}
+			if !ok {
+				continue
+			}
+			t0, t1, ok = Newton(a, b, (float64(i)+t0)/fs, (float64(j)+t1)/fs)
+			if !ok || t0 < 0 || t0 > 1 || t1 < 0 || t1 > 1 {
+				continue
+			}
+			buf = Append(buf, t0, t1)
+			if max > 0 && len(buf) == max {
+				return buf
+			}
+		}
+	}
+	return buf
+}
+
+func sample(c d2.Pt1, steps int) []line.Line {
+	out := make([]line.Line, steps)
+	prev := c.Pt1(0)
+	for i := range out {
+		cur := c.Pt1(float64(i+1) / float64(steps))
+		out[i] = line.New(prev, cur)
+		prev = cur
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package intersect
+
+import (
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurves(t *testing.T) {
+	a := ellipsearc.New(d2.Pt{0, 0}, d2.Pt{0, 0}, 1)
+	b := ellipsearc.New(d2.Pt{1, 0}, d2.Pt{1, 0}, 1)
+
+	is := Curves(a, b, 0, nil)
+	assert.Len(t, is, 2)
+	for _, ts := range is {
+		geomtest.EqualInDelta(t, a.Pt1(ts[0]), b.Pt1(ts[1]), 1e-8)
+	}
+
+	one := Curves(a, b, 0, make([][2]float64, 1))
+	assert.Len(t, one, 1)
+
+	l := line.New(d2.Pt{-2, 0.5}, d2.Pt{2, 0.5})
+	is = Curves(l, a, 10, nil)
+	assert.Len(t, is, 2)
+	for _, ts := range is {
+		geomtest.EqualInDelta(t, l.Pt1(ts[0]), a.Pt1(ts[1]), 1e-8)
+	}
+}
+
+func TestNewton(t *testing.T) {
+	a := line.New(d2.Pt{0, 0}, d2.Pt{2, 2})
+	b := line.New(d2.Pt{0, 2}, d2.Pt{2, 0})
+	t0, t1, ok := Newton(a, b, 0.2, 0.9)
+	assert.True(t, ok)
+	geomtest.Equal(t, 0.5, t0)
+	geomtest.Equal(t, 0.5, t1)
+
+	_, _, ok = Newton(a, line.New(d2.Pt{0, 1}, d2.Pt{2, 3}), 0, 0)
+	assert.False(t, ok)
+}
@@ -1,6 +1,7 @@
 package line
 
 import (
+	"math"
 	"strings"
 
 	"github.com/adamcolton/geom/calc/cmpr"
@@ -24,6 +25,16 @@ func (l Line) V1(t float64) d2.V {
 	return l.D
 }
 
+// V2 always returns the zero vector, a line does not curve.
+func (l Line) V2(t float64) d2.V {
+	return d2.V{}
+}
+
+// Inflections fulfills d2.Inflectioner. A line has no inflection points.
+func (Line) Inflections() []float64 {
+	return nil
+}
+
 // AtX Returns the value of t at x. May return Inf.
 func (l Line) AtX(x float64) float64 {
 	return (x - l.T0.X) / l.D.X
@@ -123,6 +134,18 @@ func (l Line) Closest(pt d2.Pt) d2.Pt {
 	return l.Pt1(l.ClosestT(pt))
 }
 
+// Nearest returns the parametric value of the point on the line segment closest
+// to pt and the distance to that point. Unlike ClosestT, the parametric value
+// is limited to the range [0,1].
+func (l Line) Nearest(pt d2.Pt) (float64, float64) {
+	t := 0.0
+	if m2 := l.D.Mag2(); m2 > 0 {
+		t = pt.Subtract(l.T0).Dot(l.D) / m2
+		t = math.Max(0, math.Min(1, t))
+	}
+	return t, l.Pt1(t).Distance(pt)
+}
+
 // String fulfills Stringer
 func (l Line) String() string {
 	return strings.Join([]string{
@@ -178,14 +201,46 @@ func (Line) VL(t, c int) d2.Limit {
 	return d2.LimitUndefined
 }
 
-// T applies a transform to the line returning a new line.
+// T applies a transform to the line returning a new line. Only the linear part
+// of the transform is applied to D.
 func (l Line) T(t *d2.T) Line {
 	return Line{
 		T0: t.Pt(l.T0),
-		D:  t.V(l.D),
+		D:  t.Linear(l.D),
+	}
+}
+
+// Pt1T fulfills d2.Transformer.
+func (l Line) Pt1T(t *d2.T) d2.Pt1 {
+	return l.T(t)
+}
+
+// Segment returns the line from Pt1(t0) to Pt1(t1).
+func (l Line) Segment(t0, t1 float64) Line {
+	return Line{
+		T0: l.Pt1(t0),
+		D:  l.D.Multiply(t1 - t0),
 	}
 }
 
+// Pt1Segment fulfills d2.Segmenter.
+func (l Line) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return l.Segment(t0, t1)
+}
+
+// Reverse returns the line from Pt1(1) to Pt1(0).
+func (l Line) Reverse() Line {
+	return Line{
+		T0: l.Pt1(1),
+		D:  l.D.Multiply(-1),
+	}
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (l Line) Pt1Reverse() d2.Pt1 {
+	return l.Reverse()
+}
+
 // Centroid point on the line
 func (l Line) Centroid() d2.Pt {
 	return l.Pt1(0.5)
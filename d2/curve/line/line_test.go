@@ -130,6 +130,28 @@ func (mockPt1V1) V1(t0 float64) d2.V {
 	return d2.V{2, 2 * t0}
 }
 
+func TestV2(t *testing.T) {
+	l := New(d2.Pt{1, 2}, d2.Pt{3, 5})
+	geomtest.Equal(t, d2.AssertV2{}, l)
+	geomtest.Equal(t, 0.0, d2.Curvature(l, 0.5))
+	assert.Nil(t, l.Inflections())
+}
+
+func TestNearest(t *testing.T) {
+	l := New(d2.Pt{0, 0}, d2.Pt{4, 0})
+	tt, d := l.Nearest(d2.Pt{1, 2})
+	geomtest.Equal(t, 0.25, tt)
+	geomtest.Equal(t, 2.0, d)
+
+	tt, d = l.Nearest(d2.Pt{7, 4})
+	geomtest.Equal(t, 1.0, tt)
+	geomtest.Equal(t, 5.0, d)
+
+	tt, d = Line{T0: d2.Pt{1, 1}}.Nearest(d2.Pt{1, 3})
+	geomtest.Equal(t, 0.0, tt)
+	geomtest.Equal(t, 2.0, d)
+}
+
 func TestTangentLine(t *testing.T) {
 	geomtest.Equal(t, d2.AssertV1{}, mockPt1V1{}) // confirm that the mock is valid
 	l := TangentLine(mockPt1V1{}, .5)
@@ -285,3 +307,25 @@ func TestAssertEqual(t *testing.T) {
 	err = l.AssertEqual(l2, 1e-6)
 	assert.NoError(t, err)
 }
+
+func TestTransformTranslate(t *testing.T) {
+	// D is a direction, translating it as well as T0 would move Pt1(1) twice
+	// as far as Pt1(0), giving (4, 7).
+	l := New(d2.Pt{1, 1}, d2.Pt{2, 3}).T(d2.Translate(d2.V{1, 2}).T())
+	geomtest.Equal(t, d2.Pt{2, 3}, l.Pt1(0))
+	geomtest.Equal(t, d2.Pt{3, 5}, l.Pt1(1))
+}
+
+func TestSegmentReverse(t *testing.T) {
+	l := New(d2.Pt{1, 1}, d2.Pt{3, 5})
+	s := d2.Segment(l, 0.25, 0.75)
+	geomtest.Equal(t, d2.Pt{1.5, 2}, s.Pt1(0))
+	geomtest.Equal(t, d2.Pt{2.5, 4}, s.Pt1(1))
+
+	r := d2.Reverse(l)
+	geomtest.Equal(t, d2.Pt{3, 5}, r.Pt1(0))
+	geomtest.Equal(t, d2.Pt{1, 1}, r.Pt1(1))
+
+	_, ok := d2.Transform(l, d2.Translate(d2.V{1, 2}).T()).(Line)
+	assert.True(t, ok)
+}
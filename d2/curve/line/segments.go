@@ -1,6 +1,8 @@
 package line
 
 import (
+	"math"
+
 	"github.com/adamcolton/geom/d2"
 )
 
@@ -52,3 +54,85 @@ func (ls Segments) LineIntersections(l Line, buf []float64) []float64 {
 	}
 	return buf
 }
+
+// Nearest returns the parametric value of the point on the segments closest to
+// pt and the distance to that point.
+func (ls Segments) Nearest(pt d2.Pt) (float64, float64) {
+	ln := len(ls)
+	if ln == 0 {
+		return 0, math.Inf(1)
+	}
+	if ln == 1 {
+		return 0, ls[0].Distance(pt)
+	}
+	bestT, bestD := 0.0, math.Inf(1)
+	for i := 0; i < ln-1; i++ {
+		t, d := New(ls[i], ls[i+1]).Nearest(pt)
+		if d < bestD {
+			bestT, bestD = (float64(i)+t)/float64(ln-1), d
+		}
+	}
+	return bestT, bestD
+}
+
+// Closest fulfills shape.Closest and returns the point on the segments closest
+// to pt.
+func (ls Segments) Closest(pt d2.Pt) d2.Pt {
+	t, _ := ls.Nearest(pt)
+	return ls.Pt1(t)
+}
+
+// Segment returns the Segments tracing the range [t0, t1]. The points at t0 and
+// t1 are added as end points, so the parameterization of the result is only
+// the same as the original if t0 and t1 fall on vertices.
+func (ls Segments) Segment(t0, t1 float64) Segments {
+	ln := len(ls)
+	if ln < 2 {
+		return append(Segments(nil), ls...)
+	}
+	reverse := t1 < t0
+	if reverse {
+		t0, t1 = t1, t0
+	}
+	fn := float64(ln - 1)
+	out := Segments{ls.Pt1(t0)}
+	for i := 1; i < ln-1; i++ {
+		if t := float64(i) / fn; t > t0 && t < t1 {
+			out = append(out, ls[i])
+		}
+	}
+	out = append(out, ls.Pt1(t1))
+	if reverse {
+		return out.Reverse()
+	}
+	return out
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (ls Segments) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return ls.Segment(t0, t1)
+}
+
+// Reverse returns the Segments in the opposite order.
+func (ls Segments) Reverse() Segments {
+	out := make(Segments, len(ls))
+	for i, pt := range ls {
+		out[len(ls)-1-i] = pt
+	}
+	return out
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (ls Segments) Pt1Reverse() d2.Pt1 {
+	return ls.Reverse()
+}
+
+// T applies the transform to each point.
+func (ls Segments) T(t *d2.T) Segments {
+	return t.Slice(ls)
+}
+
+// Pt1T fulfills d2.Transformer.
+func (ls Segments) Pt1T(t *d2.T) d2.Pt1 {
+	return ls.T(t)
+}
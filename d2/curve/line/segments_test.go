@@ -1,6 +1,7 @@
 package line
 
 import (
+	"math"
 	"testing"
 
 	"github.com/adamcolton/geom/d2"
@@ -63,3 +64,37 @@ func TestSegmentsEdgeCase(t *testing.T) {
 	i := s.LineIntersections(New(d2.Pt{1, 1.5}, d2.Pt{2, 1.5}), nil)
 	assert.Nil(t, i)
 }
+
+func TestSegmentsNearest(t *testing.T) {
+	s := Segments{{0, 0}, {2, 0}, {2, 2}}
+	tt, d := s.Nearest(d2.Pt{3, 1})
+	geomtest.Equal(t, 0.75, tt)
+	geomtest.Equal(t, 1.0, d)
+	geomtest.Equal(t, d2.Pt{2, 1}, s.Closest(d2.Pt{3, 1}))
+
+	tt, d = s.Nearest(d2.Pt{-1, -1})
+	geomtest.Equal(t, 0.0, tt)
+	geomtest.Equal(t, math.Sqrt2, d)
+
+	tt, d = Segments{{1, 1}}.Nearest(d2.Pt{1, 2})
+	geomtest.Equal(t, 0.0, tt)
+	geomtest.Equal(t, 1.0, d)
+}
+
+func TestSegmentsSegmentReverse(t *testing.T) {
+	ls := Segments{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}
+	s := ls.Segment(0.125, 0.625)
+	assert.Equal(t, Segments{{0.5, 0}, {1, 0}, {1, 1}, {0.5, 1}}, s)
+	assert.Equal(t, s.Reverse(), ls.Segment(0.625, 0.125))
+
+	r := ls.Reverse()
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, ls.Pt1(1-i), r.Pt1(i))
+	}
+
+	tr := d2.Translate(d2.V{1, 2}).T()
+	lt := d2.Transform(ls, tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, tr.Pt(ls.Pt1(i)), lt.Pt1(i))
+	}
+}
@@ -0,0 +1,459 @@
+package line
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+)
+
+// Crossing is an intersection between two of the segments passed to Sweep. A
+// and B are the indexes of the segments with A less than B. TA and TB are the
+// parametric values of the intersection on each.
+type Crossing struct {
+	A, B   int
+	TA, TB float64
+}
+
+// Sweep finds every pair of segments that intersect, treating each Line as the
+// segment from Pt1(0) to Pt1(1). This is the Bentley-Ottmann sweep line
+// algorithm and runs in O((n+k) log n) time for n segments with k
+// intersections. It reports the same pairs as checking every pair with
+// Intersection and keeping those where both parametric values are in [0,1].
+// Parallel segments, including overlapping collinear segments, are not
+// reported. The result is sorted by A then B.
+func Sweep(ls []Line) []Crossing {
+	out, _ := SweepPts(ls, nil, 0)
+	return out
+}
+
+// Contact is a point passed to SweepPts that lies on a segment. Pt and Seg are
+// the indexes of the point and the segment and T is the parametric value of the
+// point on the segment.
+type Contact struct {
+	Pt, Seg int
+	T       float64
+}
+
+// SweepPts is Sweep that also finds the points that lie on a segment. A point
+// is on a segment if it is within tol of it, or within the tolerance Sweep uses
+// for the segment end points if that is larger. Unlike the crossings, this
+// includes points on segments parallel to the segments that end at them. This
+// adds O((m+c) log n) time for m points with c contacts. The contacts are
+// sorted by Pt then Seg.
+func SweepPts(ls []Line, pts []d2.Pt, tol float64) ([]Crossing, []Contact) {
+	s := &sweeper{
+		ls:        ls,
+		pts:       pts,
+		seen:      make(map[[2]int]bool),
+		scheduled: make(map[[2]int]bool),
+		rand:      1,
+	}
+	var max float64
+	for _, l := range ls {
+		for _, pt := range [2]d2.Pt{l.T0, l.Pt1(1)} {
+			max = math.Max(max, math.Max(math.Abs(pt.X), math.Abs(pt.Y)))
+		}
+	}
+	for _, pt := range pts {
+		max = math.Max(max, math.Max(math.Abs(pt.X), math.Abs(pt.Y)))
+	}
+	s.eps = math.Max(1e-9*(1+max), tol)
+
+	starts := make(map[d2.Pt]*sweepEvent)
+	for i, l := range ls {
+		if l.D.X == 0 && l.D.Y == 0 {
+			continue
+		}
+		p, q := l.T0, l.Pt1(1)
+		if q.X < p.X || (q.X == p.X && q.Y < p.Y) {
+			p, q = q, p
+		}
+		sg := &sweepSeg{idx: i, p: p, q: q}
+		if q.X-p.X > s.eps {
+			sg.m = (q.Y - p.Y) / (q.X - p.X)
+		} else {
+			sg.vertical = true
+		}
+		e, ok := starts[p]
+		if !ok {
+			e = &sweepEvent{pt: p}
+			starts[p] = e
+			s.queue.push(e, s.eps)
+		}
+		e.starts = append(e.starts, sg)
+		s.queue.push(&sweepEvent{pt: q}, s.eps)
+	}
+	for i, pt := range pts {
+		s.queue.push(&sweepEvent{pt: pt, pts: []int{i}}, s.eps)
+	}
+
+	for s.queue.Len() > 0 {
+		e := s.queue.pop()
+		for s.queue.Len() > 0 && s.samePt(s.queue.peek().pt, e.pt) {
+			m := s.queue.pop()
+			e.starts = append(e.starts, m.starts...)
+			e.pts = append(e.pts, m.pts...)
+		}
+		s.handle(e)
+	}
+
+	sort.Slice(s.out, func(i, j int) bool {
+		if s.out[i].A == s.out[j].A {
+			return s.out[i].B < s.out[j].B
+		}
+		return s.out[i].A < s.out[j].A
+	})
+	sort.Slice(s.contacts, func(i, j int) bool {
+		if s.contacts[i].Pt == s.contacts[j].Pt {
+			return s.contacts[i].Seg < s.contacts[j].Seg
+		}
+		return s.contacts[i].Pt < s.contacts[j].Pt
+	})
+	return s.out, s.contacts
+}
+
+type sweepSeg struct {
+	idx      int
+	p, q     d2.Pt // p is the left end point, or the lower if vertical
+	m        float64
+	vertical bool
+	node     *sweepNode
+}
+
+type sweeper struct {
+	ls    []Line
+	root  *sweepNode
+	queue sweepQueue
+	pt    d2.Pt
+	eps   float64
+	seen  map[[2]int]bool
+	// scheduled prevents the same intersection being queued more than once
+	scheduled map[[2]int]bool
+	out       []Crossing
+	pts       []d2.Pt
+	contacts  []Contact
+	rand      uint32
+}
+
+func (s *sweeper) samePt(a, b d2.Pt) bool {
+	return math.Abs(a.X-b.X) <= s.eps && math.Abs(a.Y-b.Y) <= s.eps
+}
+
+// y of the segment on the sweep line. The sweep line is tilted infinitesimally
+// so that a vertical segment is at the y of the current event point.
+func (s *sweeper) y(sg *sweepSeg) float64 {
+	if sg.vertical {
+		return math.Max(sg.p.Y, math.Min(sg.q.Y, s.pt.Y))
+	}
+	return sg.p.Y + (s.pt.X-sg.p.X)*sg.m
+}
+
+// less orders the segments on the sweep line just after the current event
+// point.
+func (s *sweeper) less(a, b *sweepSeg) bool {
+	ya, yb := s.y(a), s.y(b)
+	if math.Abs(ya-yb) > s.eps {
+		return ya < yb
+	}
+	if a.vertical != b.vertical {
+		return b.vertical
+	}
+	if a.m != b.m {
+		return a.m < b.m
+	}
+	return a.idx < b.idx
+}
+
+func (s *sweeper) handle(e *sweepEvent) {
+	s.pt = e.pt
+
+	// segments that end at or pass through the event point are contiguous on
+	// the sweep line.
+	var through []*sweepSeg
+	for n := s.lowerBound(e.pt.Y - s.eps); n != nil && s.y(n.seg) <= e.pt.Y+s.eps; n = n.next() {
+		through = append(through, n.seg)
+	}
+
+	if len(e.pts) > 0 {
+		s.contact(e)
+	}
+
+	all := append(append([]*sweepSeg(nil), e.starts...), through...)
+	for i, a := range all {
+		for _, b := range all[i+1:] {
+			s.report(a, b)
+		}
+	}
+
+	for _, sg := range through {
+		s.remove(sg.node)
+		sg.node = nil
+	}
+	var inserted []*sweepSeg
+	for _, sg := range append(through, e.starts...) {
+		if !s.samePt(sg.q, e.pt) && sg.node == nil {
+			s.insert(sg)
+			inserted = append(inserted, sg)
+		}
+	}
+
+	if len(inserted) == 0 {
+		if above := s.lowerBound(e.pt.Y); above != nil {
+			if below := above.prev(); below != nil {
+				s.check(below.seg, above.seg)
+			}
+		}
+		return
+	}
+	for _, sg := range inserted {
+		if n := sg.node.prev(); n != nil {
+			s.check(n.seg, sg)
+		}
+		if n := sg.node.next(); n != nil {
+			s.check(sg, n.seg)
+		}
+	}
+}
+
+// contact finds the segments that the points at the event lie on. The points
+// are within eps of the event point so the segments are found the same way as
+// those passing through it.
+func (s *sweeper) contact(e *sweepEvent) {
+	lo, hi := e.pt.Y, e.pt.Y
+	for _, i := range e.pts {
+		lo, hi = math.Min(lo, s.pts[i].Y), math.Max(hi, s.pts[i].Y)
+	}
+	segs := append([]*sweepSeg(nil), e.starts...)
+	for n := s.lowerBound(lo - s.eps); n != nil && s.y(n.seg) <= hi+s.eps; n = n.next() {
+		segs = append(segs, n.seg)
+	}
+	for _, i := range e.pts {
+		pt := s.pts[i]
+		for _, sg := range segs {
+			l := s.ls[sg.idx]
+			t := l.D.Dot(pt.Subtract(l.T0)) / l.D.Mag2()
+			t = math.Max(0, math.Min(1, t))
+			if l.Pt1(t).Distance(pt) <= s.eps {
+				s.contacts = append(s.contacts, Contact{Pt: i, Seg: sg.idx, T: t})
+			}
+		}
+	}
+}
+
+// report the intersection of a and b if they intersect.
+func (s *sweeper) report(a, b *sweepSeg) {
+	i, j := a.idx, b.idx
+	if i > j {
+		i, j = j, i
+	}
+	key := [2]int{i, j}
+	if s.seen[key] {
+		return
+	}
+	ti, tj, ok := s.ls[i].Intersection(s.ls[j])
+	if !ok || ti < 0 || ti > 1 || tj < 0 || tj > 1 {
+		return
+	}
+	s.seen[key] = true
+	s.out = append(s.out, Crossing{A: i, B: j, TA: ti, TB: tj})
+}
+
+// check schedules the intersection of neighboring segments if it is after the
+// current event point.
+func (s *sweeper) check(a, b *sweepSeg) {
+	i, j := a.idx, b.idx
+	if i > j {
+		i, j = j, i
+	}
+	key := [2]int{i, j}
+	if s.seen[key] || s.scheduled[key] {
+		return
+	}
+	ti, tj, ok := s.ls[i].Intersection(s.ls[j])
+	if !ok || ti < -s.eps || ti > 1+s.eps || tj < -s.eps || tj > 1+s.eps {
+		return
+	}
+	pt := s.ls[i].Pt1(ti)
+	if pt.X < s.pt.X-s.eps || (pt.X <= s.pt.X+s.eps && pt.Y < s.pt.Y-s.eps) {
+		// already passed; this can happen from numerical error
+		s.report(a, b)
+		return
+	}
+	s.scheduled[key] = true
+	s.queue.push(&sweepEvent{pt: pt}, s.eps)
+}
+
+// lowerBound returns the first node on the sweep line with a y at or above y.
+func (s *sweeper) lowerBound(y float64) *sweepNode {
+	var out *sweepNode
+	for n := s.root; n != nil; {
+		if s.y(n.seg) >= y {
+			out = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return out
+}
+
+// sweepNode is a node in a treap holding the segments on the sweep line.
+type sweepNode struct {
+	seg                 *sweepSeg
+	pri                 uint32
+	left, right, parent *sweepNode
+}
+
+func (n *sweepNode) next() *sweepNode {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+	for n.parent != nil && n.parent.right == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func (n *sweepNode) prev() *sweepNode {
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n
+	}
+	for n.parent != nil && n.parent.left == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func (s *sweeper) priority() uint32 {
+	// xorshift
+	s.rand ^= s.rand << 13
+	s.rand ^= s.rand >> 17
+	s.rand ^= s.rand << 5
+	return s.rand
+}
+
+func (s *sweeper) insert(sg *sweepSeg) {
+	n := &sweepNode{seg: sg, pri: s.priority()}
+	sg.node = n
+	if s.root == nil {
+		s.root = n
+		return
+	}
+	cur := s.root
+	for {
+		if s.less(sg, cur.seg) {
+			if cur.left == nil {
+				cur.left = n
+				break
+			}
+			cur = cur.left
+		} else {
+			if cur.right == nil {
+				cur.right = n
+				break
+			}
+			cur = cur.right
+		}
+	}
+	n.parent = cur
+	for n.parent != nil && n.parent.pri < n.pri {
+		s.rotateUp(n)
+	}
+}
+
+func (s *sweeper) remove(n *sweepNode) {
+	for n.left != nil || n.right != nil {
+		c := n.left
+		if c == nil || (n.right != nil && n.right.pri > c.pri) {
+			c = n.right
+		}
+		s.rotateUp(c)
+	}
+	s.replaceChild(n.parent, n, nil)
+}
+
+// rotateUp moves n above its parent.
+func (s *sweeper) rotateUp(n *sweepNode) {
+	p := n.parent
+	if p.left == n {
+		p.left = n.right
+		if n.right != nil {
+			n.right.parent = p
+		}
+		n.right = p
+	} else {
+		p.right = n.left
+		if n.left != nil {
+			n.left.parent = p
+		}
+		n.left = p
+	}
+	s.replaceChild(p.parent, p, n)
+	p.parent = n
+}
+
+func (s *sweeper) replaceChild(parent, old, n *sweepNode) {
+	if n != nil {
+		n.parent = parent
+	}
+	switch {
+	case parent == nil:
+		s.root = n
+	case parent.left == old:
+		parent.left = n
+	default:
+		parent.right = n
+	}
+}
+
+type sweepEvent struct {
+	pt     d2.Pt
+	starts []*sweepSeg
+	pts    []int
+}
+
+// sweepQueue orders events by x then y.
+type sweepQueue struct {
+	events []*sweepEvent
+	eps    float64
+}
+
+func (q *sweepQueue) Len() int { return len(q.events) }
+func (q *sweepQueue) Less(i, j int) bool {
+	a, b := q.events[i].pt, q.events[j].pt
+	if math.Abs(a.X-b.X) > q.eps {
+		return a.X < b.X
+	}
+	return a.Y < b.Y
+}
+func (q *sweepQueue) Swap(i, j int)      { q.events[i], q.events[j] = q.events[j], q.events[i] }
+func (q *sweepQueue) Push(x interface{}) { q.events = append(q.events, x.(*sweepEvent)) }
+func (q *sweepQueue) Pop() interface{} {
+	ln := len(q.events) - 1
+	e := q.events[ln]
+	q.events = q.events[:ln]
+	return e
+}
+
+func (q *sweepQueue) push(e *sweepEvent, eps float64) {
+	q.eps = eps
+	heap.Push(q, e)
+}
+
+func (q *sweepQueue) pop() *sweepEvent {
+	return heap.Pop(q).(*sweepEvent)
+}
+
+func (q *sweepQueue) peek() *sweepEvent {
+	return q.events[0]
+}
@@ -0,0 +1,119 @@
+package line
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/stretchr/testify/assert"
+)
+
+func bruteForce(ls []Line) []Crossing {
+	var out []Crossing
+	for i, a := range ls {
+		for j := i + 1; j < len(ls); j++ {
+			ti, tj, ok := a.Intersection(ls[j])
+			if ok && ti >= 0 && ti <= 1 && tj >= 0 && tj <= 1 {
+				out = append(out, Crossing{A: i, B: j, TA: ti, TB: tj})
+			}
+		}
+	}
+	return out
+}
+
+func TestSweep(t *testing.T) {
+	ls := []Line{
+		New(d2.Pt{0, 0}, d2.Pt{2, 2}),
+		New(d2.Pt{0, 2}, d2.Pt{2, 0}),
+		New(d2.Pt{3, 0}, d2.Pt{3, 3}),
+		New(d2.Pt{4, 1}, d2.Pt{2, 1}),
+	}
+	assert.Equal(t, []Crossing{
+		{A: 0, B: 1, TA: 0.5, TB: 0.5},
+		{A: 2, B: 3, TA: 1.0 / 3, TB: 0.5},
+	}, Sweep(ls))
+}
+
+func TestSweepRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		ls := make([]Line, 200)
+		for j := range ls {
+			p := d2.Pt{r.Float64() * 100, r.Float64() * 100}
+			ls[j] = New(p, p.Add(d2.V{r.Float64()*20 - 10, r.Float64()*20 - 10}))
+		}
+		assert.Equal(t, bruteForce(ls), Sweep(ls))
+	}
+}
+
+func TestSweepDegenerate(t *testing.T) {
+	// a grid of horizontal and vertical lines where many lines share x values
+	// and end points lie on other lines
+	var ls []Line
+	for i := 0.0; i < 10; i++ {
+		ls = append(ls,
+			New(d2.Pt{i, 0}, d2.Pt{i, 9}),
+			New(d2.Pt{0, i}, d2.Pt{9, i}),
+		)
+	}
+	assert.Equal(t, bruteForce(ls), Sweep(ls))
+	assert.Len(t, Sweep(ls), 100)
+
+	// many lines through a single point
+	ls = nil
+	for i := 0.0; i < 12; i++ {
+		s, c := math.Sincos(i * math.Pi / 12)
+		v := d2.V{c, s}
+		ls = append(ls, New(d2.Pt{5, 5}.Add(v), d2.Pt{5, 5}.Add(v.Multiply(-1))))
+	}
+	assert.Len(t, Sweep(ls), 12*11/2)
+
+	// the sides of a polygon share end points
+	var pts []d2.Pt
+	for i := 0; i < 50; i++ {
+		s, c := math.Sincos(4 * math.Pi * float64(i) / 50)
+		r := 10 + float64(i%7)
+		pts = append(pts, d2.Pt{r * c, r * s})
+	}
+	ls = nil
+	for i, pt := range pts {
+		ls = append(ls, New(pt, pts[(i+1)%len(pts)]))
+	}
+	assert.Equal(t, bruteForce(ls), Sweep(ls))
+}
+
+func TestSweepPts(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var ls []Line
+	for i := 0; i < 100; i++ {
+		p := d2.Pt{float64(r.Intn(20)), float64(r.Intn(20))}
+		ls = append(ls, New(p, p.Add(d2.V{float64(r.Intn(9) - 4), float64(r.Intn(9) - 4)})))
+	}
+	// collinear with the segment before it
+	ls = append(ls, New(d2.Pt{3, 3}, d2.Pt{5, 5}), New(d2.Pt{4, 4}, d2.Pt{8, 8}))
+	var pts []d2.Pt
+	for _, l := range ls {
+		pts = append(pts, l.T0, l.Pt1(0.5), l.Pt1(1).Add(d2.V{1e-12, 0}))
+	}
+	for i := 0; i < 100; i++ {
+		pts = append(pts, d2.Pt{r.Float64() * 20, r.Float64() * 20})
+	}
+
+	var expected []Contact
+	for i, pt := range pts {
+		for j, l := range ls {
+			if l.D.X == 0 && l.D.Y == 0 {
+				continue
+			}
+			tt := math.Max(0, math.Min(1, l.D.Dot(pt.Subtract(l.T0))/l.D.Mag2()))
+			if l.Pt1(tt).Distance(pt) <= 1e-6 {
+				expected = append(expected, Contact{Pt: i, Seg: j, T: tt})
+			}
+		}
+	}
+	cs, got := SweepPts(ls, pts, 1e-6)
+	assert.Equal(t, expected, got)
+	assert.Equal(t, bruteForce(ls), cs)
+	assert.Contains(t, got, Contact{Pt: 3 * 101, Seg: 100, T: 0.5})
+}
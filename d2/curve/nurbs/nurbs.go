@@ -0,0 +1,380 @@
+package nurbs
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/intersect"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// NURBS is a non-uniform rational B-spline. The length of Knots must be
+// len(Pts)+Degree+1 and Weights must be the same length as Pts. The parametric
+// range [0,1] is mapped to the knot range [Knots[Degree], Knots[len(Pts)]].
+type NURBS struct {
+	Degree  int
+	Pts     []d2.Pt
+	Weights []float64
+	Knots   []float64
+}
+
+// New creates a clamped NURBS with uniformly spaced interior knots. If weights
+// is nil, all the weights are set to 1.
+func New(degree int, pts []d2.Pt, weights []float64) NURBS {
+	if weights == nil {
+		weights = NewRationalBezier(pts, nil).Weights
+	}
+	n := len(pts)
+	knots := make([]float64, n+degree+1)
+	spans := float64(n - degree)
+	for i := range knots {
+		switch {
+		case i <= degree:
+			knots[i] = 0
+		case i >= n:
+			knots[i] = 1
+		default:
+			knots[i] = float64(i-degree) / spans
+		}
+	}
+	return NURBS{
+		Degree:  degree,
+		Pts:     pts,
+		Weights: weights,
+		Knots:   knots,
+	}
+}
+
+// Circle returns a NURBS that exactly describes a circle using nine control
+// points.
+func Circle(center d2.Pt, r float64) NURBS {
+	w := math.Sqrt2 / 2
+	pts := []d2.Pt{
+		{r, 0}, {r, r}, {0, r}, {-r, r}, {-r, 0}, {-r, -r}, {0, -r}, {r, -r}, {r, 0},
+	}
+	for i := range pts {
+		pts[i] = center.Add(pts[i].V())
+	}
+	return NURBS{
+		Degree:  2,
+		Pts:     pts,
+		Weights: []float64{1, w, 1, w, 1, w, 1, w, 1},
+		Knots:   []float64{0, 0, 0, 0.25, 0.25, 0.5, 0.5, 0.75, 0.75, 1, 1, 1},
+	}
+}
+
+func (n NURBS) homogeneous() []hpt {
+	return RationalBezier{n.Pts, n.Weights}.homogeneous()
+}
+
+func (n NURBS) domain() (float64, float64) {
+	return n.Knots[n.Degree], n.Knots[len(n.Pts)]
+}
+
+func (n NURBS) u(t float64) float64 {
+	a, b := n.domain()
+	return a + t*(b-a)
+}
+
+// span returns the index k such that Knots[k] <= u < Knots[k+1] limited to the
+// valid spans of the curve.
+func span(degree, ln int, knots []float64, u float64) int {
+	if u >= knots[ln] {
+		k := ln - 1
+		for k > degree && knots[k] == knots[k+1] {
+			k--
+		}
+		return k
+	}
+	k := sort.Search(len(knots), func(i int) bool { return knots[i] > u }) - 1
+	if k < degree {
+		k = degree
+	}
+	if k > ln-1 {
+		k = ln - 1
+	}
+	return k
+}
+
+func deBoor(degree int, knots []float64, hs []hpt, u float64) hpt {
+	if len(hs) == 0 {
+		return hpt{}
+	}
+	k := span(degree, len(hs), knots, u)
+	d := make([]hpt, degree+1)
+	copy(d, hs[k-degree:k+1])
+	for r := 1; r <= degree; r++ {
+		for j := degree; j >= r; j-- {
+			i := j + k - degree
+			den := knots[i+degree-r+1] - knots[i]
+			a := 0.0
+			if den != 0 {
+				a = (u - knots[i]) / den
+			}
+			d[j] = d[j-1].lerp(d[j], a)
+		}
+	}
+	return d[degree]
+}
+
+// Pt1 fulfills d2.Pt1.
+func (n NURBS) Pt1(t float64) d2.Pt {
+	return deBoor(n.Degree, n.Knots, n.homogeneous(), n.u(t)).pt()
+}
+
+// V1 fulfills d2.V1.
+func (n NURBS) V1(t float64) d2.V {
+	if n.Degree == 0 {
+		return d2.V{}
+	}
+	hs := n.homogeneous()
+	u := n.u(t)
+	h := deBoor(n.Degree, n.Knots, hs, u)
+
+	// The derivative of a B-spline is a B-spline of one less degree.
+	p := n.Degree
+	dhs := make([]hpt, len(hs)-1)
+	for i := range dhs {
+		den := n.Knots[i+p+1] - n.Knots[i+1]
+		if den != 0 {
+			dhs[i] = hs[i+1].subtract(hs[i]).scale(float64(p) / den)
+		}
+	}
+	dh := deBoor(p-1, n.Knots[1:len(n.Knots)-1], dhs, u)
+
+	a, b := n.domain()
+	return derivative(h, dh).Multiply(b - a)
+}
+
+// L fulfills d2.Limiter.
+func (NURBS) L(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// VL fulfills d2.VLimiter.
+func (NURBS) VL(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// InsertKnot returns a NURBS describing the same curve with an additional knot
+// at u, where u is relative to the knot vector, not the parametric range.
+func (n NURBS) InsertKnot(u float64) NURBS {
+	hs, knots := insertKnot(n.Degree, n.Knots, n.homogeneous(), u)
+	r := fromHomogeneous(hs)
+	return NURBS{
+		Degree:  n.Degree,
+		Pts:     r.Pts,
+		Weights: r.Weights,
+		Knots:   knots,
+	}
+}
+
+func insertKnot(p int, knots []float64, hs []hpt, u float64) ([]hpt, []float64) {
+	// https://en.wikipedia.org/wiki/Boehm%27s_algorithm
+	k := sort.Search(len(knots), func(i int) bool { return knots[i] > u }) - 1
+	if k > len(hs)-1 {
+		k = len(hs) - 1
+	}
+	out := make([]hpt, len(hs)+1)
+	for i := range out {
+		switch {
+		case i <= k-p:
+			out[i] = hs[i]
+		case i > k:
+			out[i] = hs[i-1]
+		default:
+			a := (u - knots[i]) / (knots[i+p] - knots[i])
+			out[i] = hs[i-1].lerp(hs[i], a)
+		}
+	}
+	ks := make([]float64, 0, len(knots)+1)
+	ks = append(ks, knots[:k+1]...)
+	ks = append(ks, u)
+	ks = append(ks, knots[k+1:]...)
+	return out, ks
+}
+
+func multiplicity(knots []float64, u float64) int {
+	m := 0
+	for _, k := range knots {
+		if k == u {
+			m++
+		}
+	}
+	return m
+}
+
+// homogeneousBeziers decomposes the curve into the homogeneous control points
+// of rational Bezier segments and the knot values at the end of each segment.
+func (n NURBS) homogeneousBeziers() ([][]hpt, []float64) {
+	p := n.Degree
+	hs, knots := n.homogeneous(), n.Knots
+	a, b := n.domain()
+	var breaks []float64
+	for i := p; i <= len(n.Pts); i++ {
+		u := n.Knots[i]
+		if u < a || u > b || (len(breaks) > 0 && breaks[len(breaks)-1] == u) {
+			continue
+		}
+		breaks = append(breaks, u)
+		for m := multiplicity(knots, u); m < p; m++ {
+			hs, knots = insertKnot(p, knots, hs, u)
+		}
+	}
+
+	var out [][]hpt
+	for k := p; k < len(hs); k++ {
+		if knots[k] < knots[k+1] && knots[k] >= a && knots[k+1] <= b {
+			out = append(out, hs[k-p:k+1])
+		}
+	}
+	return out, breaks
+}
+
+// Beziers converts the NURBS to a sequence of RationalBezier curves that
+// describe the same curve.
+func (n NURBS) Beziers() []RationalBezier {
+	hbs, _ := n.homogeneousBeziers()
+	out := make([]RationalBezier, len(hbs))
+	for i, hb := range hbs {
+		out[i] = fromHomogeneous(hb)
+	}
+	return out
+}
+
+// Elevate returns a NURBS describing the same curve with the degree increased
+// by one. The curve is decomposed into rational Bezier segments which are
+// elevated and joined, so the interior knots will have full multiplicity.
+func (n NURBS) Elevate() NURBS {
+	hbs, breaks := n.homogeneousBeziers()
+	p := n.Degree + 1
+	var hs []hpt
+	knots := make([]float64, 0, len(breaks)*p+2)
+	for i, hb := range hbs {
+		e := elevate(hb)
+		if i > 0 {
+			e = e[1:]
+		}
+		hs = append(hs, e...)
+	}
+	for i, b := range breaks {
+		m := p
+		if i == 0 || i == len(breaks)-1 {
+			m++
+		}
+		for j := 0; j < m; j++ {
+			knots = append(knots, b)
+		}
+	}
+	r := fromHomogeneous(hs)
+	return NURBS{
+		Degree:  p,
+		Pts:     r.Pts,
+		Weights: r.Weights,
+		Knots:   knots,
+	}
+}
+
+// LineIntersections fulfills line.Intersector. The values are relative to the
+// line.
+func (n NURBS) LineIntersections(l line.Line, buf []float64) []float64 {
+	max := len(buf)
+	buf = buf[:0]
+	m2 := l.D.Mag2()
+	for _, t := range n.NURBSIntersections(l) {
+		buf = append(buf, l.D.Dot(n.Pt1(t).Subtract(l.T0))/m2)
+		if max > 0 && len(buf) == max {
+			break
+		}
+	}
+	return buf
+}
+
+// NURBSIntersections returns the intersection points relative to the NURBS.
+func (n NURBS) NURBSIntersections(l line.Line) []float64 {
+	bs := n.Beziers()
+	fn := float64(len(bs))
+	var out []float64
+	for i, b := range bs {
+		for _, t := range b.intersections(l) {
+			t = (float64(i) + t) / fn
+			if len(out) > 0 && intersect.Tolerance.Equal(out[len(out)-1], t) {
+				continue
+			}
+			out = append(out, t)
+		}
+	}
+	return n.fromSegments(out)
+}
+
+// fromSegments converts values that are relative to the Bezier segments into
+// values relative to the NURBS.
+func (n NURBS) fromSegments(ts []float64) []float64 {
+	_, breaks := n.homogeneousBeziers()
+	a, b := n.domain()
+	segs := float64(len(breaks) - 1)
+	for i, t := range ts {
+		idx := int(t * segs)
+		if idx >= len(breaks)-1 {
+			idx = len(breaks) - 2
+		}
+		local := t*segs - float64(idx)
+		u := breaks[idx] + local*(breaks[idx+1]-breaks[idx])
+		ts[i] = (u - a) / (b - a)
+	}
+	return ts
+}
+
+// Pt1Segment fulfills d2.Segmenter. The NURBS is wrapped in a
+// d2.SegmentWrapper.
+func (n NURBS) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return d2.SegmentWrapper{P: n, T0: t0, T1: t1}
+}
+
+// Reverse returns the NURBS traced in the opposite direction. The knot vector
+// is mirrored to match.
+func (n NURBS) Reverse() NURBS {
+	r := RationalBezier{n.Pts, n.Weights}.Reverse()
+	ln := len(n.Knots)
+	knots := make([]float64, ln)
+	if ln > 0 {
+		sum := n.Knots[0] + n.Knots[ln-1]
+		for i, k := range n.Knots {
+			knots[ln-1-i] = sum - k
+		}
+	}
+	return NURBS{
+		Degree:  n.Degree,
+		Pts:     r.Pts,
+		Weights: r.Weights,
+		Knots:   knots,
+	}
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (n NURBS) Pt1Reverse() d2.Pt1 {
+	return n.Reverse()
+}
+
+// T applies the transform to the control points. This is exact for affine
+// transforms.
+func (n NURBS) T(t *d2.T) NURBS {
+	return NURBS{
+		Degree:  n.Degree,
+		Pts:     t.Slice(n.Pts),
+		Weights: append([]float64(nil), n.Weights...),
+		Knots:   append([]float64(nil), n.Knots...),
+	}
+}
+
+// Pt1T fulfills d2.Transformer.
+func (n NURBS) Pt1T(t *d2.T) d2.Pt1 {
+	return n.T(t)
+}
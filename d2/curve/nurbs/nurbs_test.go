@@ -0,0 +1,133 @@
+package nurbs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircle(t *testing.T) {
+	c := Circle(d2.Pt{1, 2}, 3)
+	for i := 0.0; i <= 1.0; i += 0.02 {
+		geomtest.Equal(t, 3.0, c.Pt1(i).Distance(d2.Pt{1, 2}))
+	}
+	geomtest.Equal(t, d2.Pt{4, 2}, c.Pt1(0))
+	geomtest.Equal(t, d2.Pt{1, 5}, c.Pt1(0.25))
+	geomtest.Equal(t, d2.Pt{-2, 2}, c.Pt1(0.5))
+	geomtest.Equal(t, d2.Pt{4, 2}, c.Pt1(1))
+	geomtest.Equal(t, d2.AssertV1{}, c)
+}
+
+func TestMatchesBezierCurve(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 1}, {1, -1}, {1, 0}}
+	n := New(3, b, nil)
+	for i := 0.0; i <= 1.0; i += 0.1 {
+		geomtest.Equal(t, b.Pt1(i), n.Pt1(i))
+		geomtest.Equal(t, b.V1(i), n.V1(i))
+	}
+}
+
+func testNURBS() NURBS {
+	return NURBS{
+		Degree:  3,
+		Pts:     []d2.Pt{{0, 0}, {1, 3}, {3, 4}, {5, 1}, {6, 3}, {8, 0}},
+		Weights: []float64{1, 0.5, 2, 1, 1.5, 1},
+		Knots:   []float64{0, 0, 0, 0, 1, 3, 4, 4, 4, 4},
+	}
+}
+
+func TestNURBSV1(t *testing.T) {
+	geomtest.EqualInDelta(t, d2.AssertV1{}, testNURBS(), 1e-4)
+	geomtest.Equal(t, d2.AssertV1{}, New(2, []d2.Pt{{0, 0}, {1, 2}, {3, 1}, {4, 4}}, nil))
+}
+
+func TestInsertKnot(t *testing.T) {
+	n := testNURBS()
+	k := n.InsertKnot(2).InsertKnot(2).InsertKnot(0.5)
+	assert.Len(t, k.Pts, len(n.Pts)+3)
+	assert.Len(t, k.Knots, len(n.Knots)+3)
+	for i := 0.0; i <= 1.0; i += 0.05 {
+		geomtest.Equal(t, n.Pt1(i), k.Pt1(i))
+	}
+}
+
+func TestBeziers(t *testing.T) {
+	n := testNURBS()
+	bs := n.Beziers()
+	assert.Len(t, bs, 3)
+	geomtest.Equal(t, n.Pt1(0), bs[0].Pt1(0))
+	geomtest.Equal(t, n.Pt1(0.25), bs[1].Pt1(0))
+	geomtest.Equal(t, n.Pt1(0.75), bs[2].Pt1(0))
+	geomtest.Equal(t, n.Pt1(1), bs[2].Pt1(1))
+	for i := 0.0; i <= 1.0; i += 0.1 {
+		// first span is u=[0,1] or t=[0,0.25]
+		geomtest.Equal(t, n.Pt1(i*0.25), bs[0].Pt1(i))
+		geomtest.Equal(t, n.Pt1(0.25+i*0.5), bs[1].Pt1(i))
+	}
+
+	bs = Circle(d2.Pt{0, 0}, 1).Beziers()
+	assert.Len(t, bs, 4)
+	for _, b := range bs {
+		assert.Len(t, b.Pts, 3)
+		geomtest.Equal(t, 1.0, b.Pt1(0.3).Distance(d2.Pt{0, 0}))
+	}
+}
+
+func TestElevate(t *testing.T) {
+	n := testNURBS()
+	e := n.Elevate()
+	assert.Equal(t, 4, e.Degree)
+	assert.Equal(t, len(e.Pts)+e.Degree+1, len(e.Knots))
+	for i := 0.0; i <= 1.0; i += 0.05 {
+		geomtest.Equal(t, n.Pt1(i), e.Pt1(i))
+	}
+
+	c := Circle(d2.Pt{0, 0}, 1).Elevate()
+	for i := 0.0; i <= 1.0; i += 0.05 {
+		geomtest.Equal(t, 1.0, c.Pt1(i).Distance(d2.Pt{0, 0}))
+	}
+}
+
+func TestNURBSLineIntersections(t *testing.T) {
+	c := Circle(d2.Pt{0, 0}, 2)
+	l := line.New(d2.Pt{-3, 1}, d2.Pt{3, 1})
+	ts := c.NURBSIntersections(l)
+	if assert.Len(t, ts, 2) {
+		for _, tt := range ts {
+			geomtest.Equal(t, 1.0, c.Pt1(tt).Y)
+		}
+	}
+	lts := c.LineIntersections(l, nil)
+	if assert.Len(t, lts, 2) {
+		x := math.Sqrt(3)
+		geomtest.Equal(t, d2.Pt{x, 1}, l.Pt1(lts[0]))
+		geomtest.Equal(t, d2.Pt{-x, 1}, l.Pt1(lts[1]))
+	}
+	assert.Len(t, c.LineIntersections(l, make([]float64, 1)), 1)
+
+	n := testNURBS()
+	l = line.New(d2.Pt{0, 1.5}, d2.Pt{1, 1.5})
+	for _, tt := range n.NURBSIntersections(l) {
+		geomtest.Equal(t, 1.5, n.Pt1(tt).Y)
+	}
+}
+
+func TestNURBSSegmentReverseT(t *testing.T) {
+	n := Circle(d2.Pt{1, 2}, 3)
+	n.Knots = []float64{0, 0, 0, 0.1, 0.1, 0.5, 0.5, 0.6, 0.6, 1, 1, 1}
+	s := d2.Segment(n, 0.2, 0.7)
+	r := n.Reverse()
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	nt := d2.Transform(n, tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, n.Pt1(0.2+i*0.5), s.Pt1(i))
+		geomtest.Equal(t, n.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(n.Pt1(i)), nt.Pt1(i))
+	}
+	geomtest.EqualInDelta(t, d2.AssertV1{}, r, 1e-4)
+}
@@ -0,0 +1,281 @@
+// Package nurbs provides rational Bezier curves and non-uniform rational
+// B-splines. Unlike bezier.Bezier, these can represent conic sections, such as
+// circles, exactly.
+package nurbs
+
+import (
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/curve/poly"
+)
+
+// hpt is a point in homogeneous coordinates, X and Y are scaled by W.
+type hpt struct {
+	X, Y, W float64
+}
+
+func homogeneous(pt d2.Pt, w float64) hpt {
+	return hpt{pt.X * w, pt.Y * w, w}
+}
+
+func (h hpt) pt() d2.Pt {
+	return d2.Pt{h.X / h.W, h.Y / h.W}
+}
+
+func (h hpt) lerp(h2 hpt, t float64) hpt {
+	s := 1 - t
+	return hpt{h.X*s + h2.X*t, h.Y*s + h2.Y*t, h.W*s + h2.W*t}
+}
+
+func (h hpt) scale(s float64) hpt {
+	return hpt{h.X * s, h.Y * s, h.W * s}
+}
+
+func (h hpt) subtract(h2 hpt) hpt {
+	return hpt{h.X - h2.X, h.Y - h2.Y, h.W - h2.W}
+}
+
+// derivative returns the derivative of the rational curve given the
+// homogeneous point and the homogeneous derivative at the same parametric
+// value.
+func derivative(h, dh hpt) d2.V {
+	pt := h.pt()
+	return d2.V{
+		X: (dh.X - dh.W*pt.X) / h.W,
+		Y: (dh.Y - dh.W*pt.Y) / h.W,
+	}
+}
+
+// RationalBezier is a Bezier curve where each control point has a weight. If
+// all the weights are equal, it is the same as bezier.Bezier.
+type RationalBezier struct {
+	Pts     []d2.Pt
+	Weights []float64
+}
+
+// NewRationalBezier creates a RationalBezier. If no weights are given, all the
+// weights are set to 1.
+func NewRationalBezier(pts []d2.Pt, weights []float64) RationalBezier {
+	if weights == nil {
+		weights = make([]float64, len(pts))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	return RationalBezier{
+		Pts:     pts,
+		Weights: weights,
+	}
+}
+
+// Conic returns a quadratic RationalBezier describing a conic section from p0
+// to p2 with control point p1. A weight of less than 1 describes an ellipse,
+// 1 a parabola and greater than 1 a hyperbola. An arc of a circle spanning an
+// angle a has a weight of cos(a/2).
+func Conic(p0, p1, p2 d2.Pt, w float64) RationalBezier {
+	return RationalBezier{
+		Pts:     []d2.Pt{p0, p1, p2},
+		Weights: []float64{1, w, 1},
+	}
+}
+
+func (r RationalBezier) homogeneous() []hpt {
+	out := make([]hpt, len(r.Pts))
+	for i, pt := range r.Pts {
+		out[i] = homogeneous(pt, r.Weights[i])
+	}
+	return out
+}
+
+func fromHomogeneous(hs []hpt) RationalBezier {
+	r := RationalBezier{
+		Pts:     make([]d2.Pt, len(hs)),
+		Weights: make([]float64, len(hs)),
+	}
+	for i, h := range hs {
+		r.Pts[i] = h.pt()
+		r.Weights[i] = h.W
+	}
+	return r
+}
+
+func deCasteljau(hs []hpt, t float64, buf []hpt) hpt {
+	buf = append(buf[:0], hs...)
+	for ln := len(buf) - 1; ln > 0; ln-- {
+		for i := 0; i < ln; i++ {
+			buf[i] = buf[i].lerp(buf[i+1], t)
+		}
+	}
+	return buf[0]
+}
+
+func diffHomogeneous(hs []hpt) []hpt {
+	ln := len(hs) - 1
+	out := make([]hpt, ln)
+	for i := range out {
+		out[i] = hs[i+1].subtract(hs[i]).scale(float64(ln))
+	}
+	return out
+}
+
+// Pt1 fulfills d2.Pt1.
+func (r RationalBezier) Pt1(t float64) d2.Pt {
+	hs := r.homogeneous()
+	return deCasteljau(hs, t, hs).pt()
+}
+
+// V1 fulfills d2.V1.
+func (r RationalBezier) V1(t float64) d2.V {
+	hs := r.homogeneous()
+	if len(hs) < 2 {
+		return d2.V{}
+	}
+	dhs := diffHomogeneous(hs)
+	return derivative(deCasteljau(hs, t, nil), deCasteljau(dhs, t, dhs))
+}
+
+// L fulfills d2.Limiter. A rational curve may have poles outside of [0,1].
+func (RationalBezier) L(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// VL fulfills d2.VLimiter.
+func (RationalBezier) VL(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// Elevate returns a RationalBezier describing the same curve with one more
+// control point.
+func (r RationalBezier) Elevate() RationalBezier {
+	return fromHomogeneous(elevate(r.homogeneous()))
+}
+
+func elevate(hs []hpt) []hpt {
+	n := len(hs)
+	out := make([]hpt, n+1)
+	out[0], out[n] = hs[0], hs[n-1]
+	fn := float64(n)
+	for i := 1; i < n; i++ {
+		a := float64(i) / fn
+		out[i] = hs[i].lerp(hs[i-1], a)
+	}
+	return out
+}
+
+// LineIntersections fulfills line.Intersector. Only intersections where the
+// curve is in the range [0,1] are returned and the values are relative to the
+// line.
+func (r RationalBezier) LineIntersections(l line.Line, buf []float64) []float64 {
+	max := len(buf)
+	buf = buf[:0]
+	ts := r.intersections(l)
+	m2 := l.D.Mag2()
+	for _, t := range ts {
+		buf = append(buf, l.D.Dot(r.Pt1(t).Subtract(l.T0))/m2)
+		if max > 0 && len(buf) == max {
+			break
+		}
+	}
+	return buf
+}
+
+// RationalIntersections returns the intersection points relative to the
+// RationalBezier.
+func (r RationalBezier) RationalIntersections(l line.Line) []float64 {
+	return r.intersections(l)
+}
+
+func (r RationalBezier) intersections(l line.Line) []float64 {
+	// A point is on the line when n·(N(t) - W(t)*T0) = 0 where n is normal to
+	// the line. That is a 1D Bezier curve with control values w*n·(P-T0).
+	n := d2.V{-l.D.Y, l.D.X}
+	cs := make(bezier.Bezier, len(r.Pts))
+	for i, pt := range r.Pts {
+		cs[i].X = r.Weights[i] * n.Dot(pt.Subtract(l.T0))
+	}
+	roots := poly.NewBezier(cs).X().Roots(nil)
+	out := roots[:0]
+	for _, t := range roots {
+		if t >= -rootPad && t <= 1+rootPad {
+			if t < 0 {
+				t = 0
+			} else if t > 1 {
+				t = 1
+			}
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+const rootPad = 1e-12
+
+// Segment returns the RationalBezier describing the range [t0, t1] of the curve.
+func (r RationalBezier) Segment(t0, t1 float64) RationalBezier {
+	// Each control point of the segment is the blossom of the homogeneous curve
+	// with t0 repeated n-i times and t1 repeated i times.
+	hs := r.homogeneous()
+	n := len(hs) - 1
+	out := make([]hpt, len(hs))
+	buf := make([]hpt, len(hs))
+	for i := range out {
+		copy(buf, hs)
+		for ln := n; ln > 0; ln-- {
+			t := t0
+			if n-ln < i {
+				t = t1
+			}
+			for j := 0; j < ln; j++ {
+				buf[j] = buf[j].lerp(buf[j+1], t)
+			}
+		}
+		out[i] = buf[0]
+	}
+	return fromHomogeneous(out)
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (r RationalBezier) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return r.Segment(t0, t1)
+}
+
+// Reverse returns the RationalBezier with the control points and weights in
+// the opposite order.
+func (r RationalBezier) Reverse() RationalBezier {
+	ln := len(r.Pts)
+	out := RationalBezier{
+		Pts:     make([]d2.Pt, ln),
+		Weights: make([]float64, ln),
+	}
+	for i := range r.Pts {
+		out.Pts[ln-1-i] = r.Pts[i]
+		out.Weights[ln-1-i] = r.Weights[i]
+	}
+	return out
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (r RationalBezier) Pt1Reverse() d2.Pt1 {
+	return r.Reverse()
+}
+
+// T applies the transform to the control points. This is exact for affine
+// transforms.
+func (r RationalBezier) T(t *d2.T) RationalBezier {
+	return RationalBezier{
+		Pts:     t.Slice(r.Pts),
+		Weights: append([]float64(nil), r.Weights...),
+	}
+}
+
+// Pt1T fulfills d2.Transformer.
+func (r RationalBezier) Pt1T(t *d2.T) d2.Pt1 {
+	return r.T(t)
+}
@@ -0,0 +1,82 @@
+package nurbs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConic(t *testing.T) {
+	c := Conic(d2.Pt{1, 0}, d2.Pt{1, 1}, d2.Pt{0, 1}, math.Cos(math.Pi/4))
+	for i := 0.0; i <= 1.0; i += 0.05 {
+		geomtest.Equal(t, 1.0, c.Pt1(i).Distance(d2.Pt{0, 0}))
+	}
+	geomtest.Equal(t, d2.Pt{1, 0}, c.Pt1(0))
+	geomtest.Equal(t, d2.Pt{0, 1}, c.Pt1(1))
+	geomtest.Equal(t, d2.AssertV1{}, c)
+	assert.Equal(t, d2.LimitBounded, c.L(1, 1))
+	assert.Equal(t, d2.LimitUndefined, c.VL(2, 1))
+}
+
+func TestMatchesBezier(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 1}, {1, -1}, {1, 0}}
+	r := NewRationalBezier(b, nil)
+	for i := 0.0; i <= 1.0; i += 0.1 {
+		geomtest.Equal(t, b.Pt1(i), r.Pt1(i))
+		geomtest.Equal(t, b.V1(i), r.V1(i))
+	}
+}
+
+func TestRationalElevate(t *testing.T) {
+	r := RationalBezier{
+		Pts:     []d2.Pt{{0, 0}, {1, 3}, {3, 2}, {4, 0}},
+		Weights: []float64{1, 2, 0.5, 1},
+	}
+	e := r.Elevate()
+	assert.Len(t, e.Pts, 5)
+	for i := 0.0; i <= 1.0; i += 0.1 {
+		geomtest.Equal(t, r.Pt1(i), e.Pt1(i))
+	}
+	geomtest.Equal(t, d2.AssertV1{}, r)
+}
+
+func TestRationalLineIntersections(t *testing.T) {
+	c := Conic(d2.Pt{1, 0}, d2.Pt{1, 1}, d2.Pt{0, 1}, math.Cos(math.Pi/4))
+	l := line.New(d2.Pt{0, 0}, d2.Pt{1, 1})
+
+	ts := c.RationalIntersections(l)
+	if assert.Len(t, ts, 1) {
+		geomtest.Equal(t, 0.5, ts[0])
+	}
+
+	lts := c.LineIntersections(l, nil)
+	if assert.Len(t, lts, 1) {
+		geomtest.Equal(t, math.Sqrt2/2, lts[0])
+	}
+
+	// the full quadratic crosses y=0.5 twice, but only once on [0,1]
+	l = line.New(d2.Pt{-2, 0.5}, d2.Pt{2, 0.5})
+	lts = c.LineIntersections(l, nil)
+	if assert.Len(t, lts, 1) {
+		geomtest.Equal(t, d2.Pt{math.Sqrt(0.75), 0.5}, l.Pt1(lts[0]))
+	}
+}
+
+func TestRationalSegmentReverseT(t *testing.T) {
+	r := Conic(d2.Pt{1, 0}, d2.Pt{1, 1}, d2.Pt{0, 1}, math.Sqrt2/2)
+	s := r.Segment(0.2, 0.7)
+	rv := r.Reverse()
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	rt := r.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, r.Pt1(0.2+i*0.5), s.Pt1(i))
+		geomtest.Equal(t, r.Pt1(1-i), rv.Pt1(i))
+		geomtest.Equal(t, tr.Pt(r.Pt1(i)), rt.Pt1(i))
+		geomtest.Equal(t, 1.0, s.Pt1(i).Distance(d2.Pt{0, 0}))
+	}
+}
@@ -0,0 +1,168 @@
+// Package offset computes curves parallel to other curves and the outlines of
+// stroked curves. A positive distance offsets to the left of the direction of
+// travel, which is counter-clockwise from the tangent.
+package offset
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/curve/path"
+)
+
+var (
+	// MaxDepth limits the number of times a curve is subdivided when it is
+	// approximated by a chain of Bezier curves.
+	MaxDepth = 12
+	// Samples is the number of points on each Bezier curve that are checked
+	// against the true offset curve.
+	Samples = 8
+	// MiterLimit is the maximum ratio of the miter length to the offset
+	// distance. Beyond this, corners are beveled.
+	MiterLimit = 4.0
+)
+
+// Line returns the line parallel to l at distance d.
+func Line(l line.Line, d float64) line.Line {
+	return line.Line{
+		T0: l.T0.Add(unitNormal(l.D).Multiply(d)),
+		D:  l.D,
+	}
+}
+
+// Segments returns the segments parallel to s at distance d. Corners are
+// joined with a miter unless that exceeds MiterLimit in which case they are
+// beveled.
+func Segments(s line.Segments, d float64) line.Segments {
+	var ls []line.Line
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[i-1] {
+			ls = append(ls, Line(line.New(s[i-1], s[i]), d))
+		}
+	}
+	if len(ls) == 0 {
+		return append(line.Segments(nil), s...)
+	}
+
+	out := line.Segments{ls[0].Pt1(0)}
+	for i := 1; i < len(ls); i++ {
+		a, b := ls[i-1], ls[i]
+		t, _, ok := a.Intersection(b)
+		if ok {
+			m := a.Pt1(t)
+			// the vertex on the original segments is a.Pt1(1) offset by -d
+			if d == 0 || a.Pt1(1).Distance(m)/math.Abs(d) <= math.Sqrt(MiterLimit*MiterLimit-1) {
+				out = append(out, m)
+				continue
+			}
+		}
+		out = append(out, a.Pt1(1), b.Pt1(0))
+	}
+	return append(out, ls[len(ls)-1].Pt1(1))
+}
+
+// Bezier approximates the curve parallel to b at distance d with a chain of
+// cubic Bezier curves. No point on the chain will be further than tolerance
+// from the true offset curve at the same parametric value.
+func Bezier(b bezier.Bezier, d, tolerance float64) []bezier.Bezier {
+	return Pt1V1(b, d, tolerance)
+}
+
+// EllipseArc returns a curve parallel to the arc at distance d. The offset of a
+// circular arc is also a circular arc and will be exact. The offset of an
+// elliptic arc is not an ellipse so it is approximated with Bezier curves.
+func EllipseArc(e *ellipsearc.EllipseArc, d, tolerance float64) path.Path {
+	if c, ok := circle(e, d); ok {
+		return path.Path{c}
+	}
+	bs := Pt1V1(e, d, tolerance)
+	out := make(path.Path, len(bs))
+	for i, b := range bs {
+		out[i] = b
+	}
+	return out
+}
+
+func circle(e *ellipsearc.EllipseArc, d float64) (*ellipsearc.EllipseArc, bool) {
+	major, minor := e.Axis()
+	if major != minor {
+		return nil, false
+	}
+	// Proceeding counter-clockwise, the left side is towards the center.
+	r := major + d
+	if e.Length > 0 {
+		r = major - d
+	}
+	if r <= 0 {
+		return nil, false
+	}
+	a, _, _ := e.Angle()
+	c := ellipsearc.NewAxis(e.Centroid(), r, r, a)
+	c.Start, c.Length = e.Start, e.Length
+	return c, true
+}
+
+// Pt1V1 approximates the curve parallel to any curve at distance d with a chain
+// of cubic Bezier curves. Each Bezier matches the position and derivative of
+// the offset curve at its end points and the range is subdivided until no
+// sample is further than tolerance from the offset curve.
+func Pt1V1(c d2.Pt1V1, d, tolerance float64) []bezier.Bezier {
+	o := &offsetter{
+		c:   c,
+		d:   d,
+		tol: tolerance,
+	}
+	o.fit(0, 1, o.pt(0), o.pt(1), 0)
+	return o.out
+}
+
+type offsetter struct {
+	c   d2.Pt1V1
+	d   float64
+	tol float64
+	out []bezier.Bezier
+}
+
+func (o *offsetter) pt(t float64) d2.Pt {
+	return o.c.Pt1(t).Add(d2.Normal(o.c, t).Multiply(o.d))
+}
+
+// v is the derivative of the offset curve. For a unit normal N, N' = -k*B' so
+// the derivative of B + d*N is B'*(1 - d*k).
+func (o *offsetter) v(t float64) d2.V {
+	return o.c.V1(t).Multiply(1 - o.d*d2.Curvature(o.c, t))
+}
+
+func (o *offsetter) fit(t0, t1 float64, p0, p3 d2.Pt, depth int) {
+	s := (t1 - t0) / 3
+	b := bezier.Bezier{
+		p0,
+		p0.Add(o.v(t0).Multiply(s)),
+		p3.Add(o.v(t1).Multiply(-s)),
+		p3,
+	}
+	if depth < MaxDepth {
+		for i := 1; i < Samples; i++ {
+			st := float64(i) / float64(Samples)
+			if b.Pt1(st).Distance(o.pt(t0+st*(t1-t0))) > o.tol {
+				m := (t0 + t1) / 2
+				pm := o.pt(m)
+				o.fit(t0, m, p0, pm, depth+1)
+				o.fit(m, t1, pm, p3, depth+1)
+				return
+			}
+		}
+	}
+	o.out = append(o.out, b)
+}
+
+func unitNormal(v d2.V) d2.V {
+	m := v.Mag()
+	if m == 0 {
+		return d2.V{}
+	}
+	return d2.V{-v.Y / m, v.X / m}
+}
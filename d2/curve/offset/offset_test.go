@@ -0,0 +1,83 @@
+package offset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/angle"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/closest"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLine(t *testing.T) {
+	l := line.New(d2.Pt{0, 0}, d2.Pt{2, 0})
+	geomtest.Equal(t, line.New(d2.Pt{0, 1}, d2.Pt{2, 1}), Line(l, 1))
+	geomtest.Equal(t, line.New(d2.Pt{0, -1}, d2.Pt{2, -1}), Line(l, -1))
+}
+
+func TestSegments(t *testing.T) {
+	s := line.Segments{{0, 0}, {2, 0}, {2, 2}}
+	geomtest.Equal(t, line.Segments{{0, 1}, {1, 1}, {1, 2}}, Segments(s, 1))
+	geomtest.Equal(t, line.Segments{{0, -1}, {3, -1}, {3, 2}}, Segments(s, -1))
+
+	// a sharp corner is beveled
+	s = line.Segments{{0, 0}, {10, 0}, {0, 1}}
+	o := Segments(s, -1)
+	assert.Len(t, o, 4)
+}
+
+func assertOffset(t *testing.T, c d2.Pt1V1, d, tol float64, pts []d2.Pt) {
+	for _, pt := range pts {
+		_, dist := closest.Pt1(c, pt)
+		assert.InDelta(t, math.Abs(d), dist, tol)
+	}
+}
+
+func TestBezier(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+	tol := 1e-4
+	for _, d := range []float64{1, -1, 3} {
+		bs := Bezier(b, d, tol)
+		assert.True(t, len(bs) > 1)
+		geomtest.Equal(t, d2.Pt{-d, 0}, bs[0][0])
+		geomtest.Equal(t, d2.Pt{10 + d, 0}, bs[len(bs)-1][3])
+		for i, o := range bs {
+			if i > 0 {
+				geomtest.Equal(t, bs[i-1][3], o[0])
+			}
+			var pts []d2.Pt
+			for j := 0.0; j <= 1.0; j += 0.1 {
+				pts = append(pts, o.Pt1(j))
+			}
+			assertOffset(t, b, d, 2*tol, pts)
+		}
+	}
+}
+
+func TestEllipseArc(t *testing.T) {
+	c := ellipsearc.NewAxis(d2.Pt{1, 1}, 2, 2, 0)
+	c.Start, c.Length = 0.5, 2
+	p := EllipseArc(c, 0.5, 1e-4)
+	if assert.Len(t, p, 1) {
+		for i := 0.0; i <= 1.0; i += 0.1 {
+			geomtest.Equal(t, 1.5, p.Pt1(i).Distance(d2.Pt{1, 1}))
+		}
+	}
+	c.Length = -2
+	p = EllipseArc(c, 0.5, 1e-4)
+	geomtest.Equal(t, 2.5, p.Pt1(0.5).Distance(d2.Pt{1, 1}))
+
+	e := ellipsearc.NewAxis(d2.Pt{1, 1}, 3, 2, angle.Deg(20))
+	p = EllipseArc(e, -0.5, 1e-4)
+	assert.True(t, len(p) > 1)
+	var pts []d2.Pt
+	for i := 0.0; i <= 1.0; i += 0.01 {
+		pts = append(pts, p.Pt1(i))
+	}
+	assertOffset(t, e, 0.5, 2e-4, pts)
+}
@@ -0,0 +1,106 @@
+package offset
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+)
+
+// Polygon grows the polygon by d, or shrinks it if d is negative, joining the
+// sides at the outside of each corner with j. Overlaps in the offset are
+// removed so a polygon that is shrunk may split into several polygons or
+// vanish entirely. Outer boundaries proceed counter-clockwise and holes proceed
+// clockwise. RoundJoin is flattened to within DefaultTolerance.
+func Polygon(p polygon.Polygon, d float64, j Join) []polygon.Polygon {
+	if p.SignedArea() < 0 {
+		p = p.Reverse()
+	}
+	return polygon.Fill(polygon.Positive, ring(p, d, j))
+}
+
+// Region grows the region by d, or shrinks it if d is negative. Growing the
+// region shrinks the holes. See Polygon.
+func Region(r polygon.Region, d float64, j Join) polygon.MultiPolygon {
+	r = polygon.NewRegion(r.Outer, r.Holes...)
+	var rings []polygon.Polygon
+	for _, rg := range r.Rings() {
+		rings = append(rings, ring(rg, d, j))
+	}
+	return polygon.NewMultiPolygon(polygon.Fill(polygon.Positive, rings...))
+}
+
+// ring offsets each side of p to the right by d and joins them. The result
+// overlaps itself on the inside of corners and where it has shrunk past
+// collapsing. Those parts either have a winding number that is not positive or
+// are covered by another part of the ring so they are removed when filled with
+// the Positive rule.
+func ring(p polygon.Polygon, d float64, j Join) polygon.Polygon {
+	var pts []d2.Pt
+	for i, pt := range p {
+		if pt != p[(i+1)%len(p)] {
+			pts = append(pts, pt)
+		}
+	}
+	ln := len(pts)
+	if ln < 3 || d == 0 {
+		return polygon.Polygon(pts)
+	}
+
+	var out polygon.Polygon
+	for i, cur := range pts {
+		prev, next := pts[(i+ln-1)%ln], pts[(i+1)%ln]
+		tin, tout := cur.Subtract(prev), next.Subtract(cur)
+		oin, oout := unitNormal(tin).Multiply(-d), unitNormal(tout).Multiply(-d)
+		a, b := cur.Add(oin), cur.Add(oout)
+
+		cross := tin.Cross(tout)
+		if cross*d < 0 {
+			// Going through the vertex keeps the winding correct.
+			out = append(out, a, cur, b)
+			continue
+		}
+		if cross == 0 && tin.Dot(tout) > 0 {
+			out = append(out, a)
+			continue
+		}
+
+		switch j {
+		case RoundJoin:
+			out = append(out, roundCorner(cur, oin, oout, d)...)
+			continue
+		case MiterJoin:
+			sum := oin.Add(oout)
+			if m2 := sum.Mag2(); m2 > 0 && 2*math.Abs(d)/math.Sqrt(m2) <= MiterLimit {
+				out = append(out, cur.Add(sum.Multiply(2*d*d/m2)))
+				continue
+			}
+		case SquareJoin:
+			q0, q1 := squareCorner(tin, tout, oin, oout)
+			out = append(out, a, cur.Add(q0), cur.Add(q1), b)
+			continue
+		}
+		out = append(out, a, b)
+	}
+	return out
+}
+
+// roundCorner returns the points of an arc around pt from pt+v0 to pt+v1. A
+// half circle proceeds counter-clockwise if d is positive.
+func roundCorner(pt d2.Pt, v0, v1 d2.V, d float64) []d2.Pt {
+	r := math.Abs(d)
+	a := math.Atan2(v0.Cross(v1), v0.Dot(v1))
+	if math.Abs(a) >= math.Pi-1e-12 {
+		a = math.Copysign(math.Pi, d)
+	}
+	n := 1
+	if DefaultTolerance < r {
+		n = int(math.Ceil(math.Abs(a) / (2 * math.Acos(1-DefaultTolerance/r))))
+	}
+	out := make([]d2.Pt, 0, n+1)
+	for i := 0; i <= n; i++ {
+		s, c := math.Sincos(a * float64(i) / float64(n))
+		out = append(out, pt.Add(d2.V{v0.X*c - v0.Y*s, v0.X*s + v0.Y*c}))
+	}
+	return out
+}
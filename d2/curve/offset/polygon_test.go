@@ -0,0 +1,91 @@
+package offset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolygon(t *testing.T) {
+	sq := polygon.Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	cut := (math.Sqrt2 - 1) * (math.Sqrt2 - 1)
+	tt := map[string]struct {
+		Join
+		expected float64
+	}{
+		"miter":  {MiterJoin, 16},
+		"bevel":  {BevelJoin, 14},
+		"square": {SquareJoin, 16 - 4*cut},
+	}
+	for n, tc := range tt {
+		t.Run(n, func(t *testing.T) {
+			ps := Polygon(sq, 1, tc.Join)
+			if assert.Len(t, ps, 1) {
+				geomtest.Equal(t, tc.expected, ps[0].SignedArea())
+			}
+			// orientation of the input does not matter
+			ps = Polygon(sq.Reverse(), 1, tc.Join)
+			geomtest.Equal(t, tc.expected, ps[0].SignedArea())
+		})
+	}
+
+	ps := Polygon(sq, 1, RoundJoin)
+	if assert.Len(t, ps, 1) {
+		assert.InDelta(t, 12+math.Pi, ps[0].Area(), 1e-2)
+	}
+
+	ps = Polygon(sq, -0.5, MiterJoin)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 1.0, ps[0].Area())
+		assert.True(t, ps[0].Contains(d2.Pt{1, 1}))
+	}
+	assert.Len(t, Polygon(sq, -1.5, MiterJoin), 0)
+}
+
+func TestPolygonConcave(t *testing.T) {
+	// growing the inside corner of an L is a miter that is not overlapped
+	l := polygon.Polygon{{0, 0}, {3, 0}, {3, 1}, {1, 1}, {1, 3}, {0, 3}}
+	ps := Polygon(l, 1, MiterJoin)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 21.0, ps[0].Area())
+		assert.Len(t, ps[0], 6)
+	}
+
+	// two squares joined by a thin bar split apart when shrunk
+	db := polygon.Polygon{
+		{0, 0}, {4, 0}, {4, 1.5}, {6, 1.5}, {6, 0}, {10, 0},
+		{10, 4}, {6, 4}, {6, 2.5}, {4, 2.5}, {4, 4}, {0, 4},
+	}
+	ps = Polygon(db, -0.75, MiterJoin)
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, 6.25, ps[0].Area())
+		geomtest.Equal(t, 6.25, ps[1].Area())
+	}
+}
+
+func TestRegion(t *testing.T) {
+	o := polygon.NewRegion(
+		polygon.Polygon{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		polygon.Polygon{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+	)
+	m := Region(o, 0.5, MiterJoin)
+	if assert.Len(t, m, 1) {
+		assert.Len(t, m[0].Holes, 1)
+		geomtest.Equal(t, 24.0, m.Area())
+	}
+
+	m = Region(o, 1.5, MiterJoin)
+	if assert.Len(t, m, 1) {
+		assert.Len(t, m[0].Holes, 0)
+		geomtest.Equal(t, 49.0, m.Area())
+	}
+
+	m = Region(o, -0.25, MiterJoin)
+	if assert.Len(t, m, 1) {
+		geomtest.Equal(t, 3.5*3.5-2.5*2.5, m.Area())
+	}
+}
@@ -0,0 +1,305 @@
+package offset
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/flatten"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/curve/path"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+)
+
+// Join is the shape used to connect the outside of a corner.
+type Join byte
+
+const (
+	// MiterJoin extends the edges until they meet, falling back to BevelJoin if
+	// the ratio of the miter length to the half width exceeds MiterLimit.
+	MiterJoin Join = iota
+	// RoundJoin connects the edges with a circular arc.
+	RoundJoin
+	// BevelJoin connects the edges with a straight line.
+	BevelJoin
+	// SquareJoin extends the edges past the corner by the offset distance and
+	// connects them with a line perpendicular to the bisector of the corner.
+	SquareJoin
+)
+
+// Cap is the shape used at the ends of an open curve.
+type Cap byte
+
+const (
+	// ButtCap ends the stroke at the end of the curve.
+	ButtCap Cap = iota
+	// RoundCap ends the stroke with a semicircle.
+	RoundCap
+	// SquareCap extends the stroke by half the width past the end of the curve.
+	SquareCap
+)
+
+// DefaultTolerance is used by Stroker when Tolerance is not set.
+var DefaultTolerance = 1e-3
+
+// Stroker generates the outline of a curve drawn with a pen of the given
+// Width.
+type Stroker struct {
+	Width float64
+	Join
+	Cap
+	// MiterLimit overrides the package MiterLimit if it is not zero.
+	MiterLimit float64
+	// Tolerance is the maximum error allowed when approximating offset curves
+	// and when flattening to polygons.
+	Tolerance float64
+}
+
+func (s Stroker) tolerance() float64 {
+	if s.Tolerance == 0 {
+		return DefaultTolerance
+	}
+	return s.Tolerance
+}
+
+func (s Stroker) miterLimit() float64 {
+	if s.MiterLimit == 0 {
+		return MiterLimit
+	}
+	return s.MiterLimit
+}
+
+// Stroke returns the outline of the curve as closed paths. An open curve
+// produces a single path. A closed curve, one that ends where it starts,
+// produces two paths; the left side followed by the right side. The outline
+// may overlap itself on the inside of corners and should be filled with the
+// nonzero rule.
+func (s Stroker) Stroke(c d2.Pt1) []path.Path {
+	pieces := decompose(c)
+	if len(pieces) == 0 {
+		return nil
+	}
+	h := s.Width / 2
+	closed := cmprZero(pieces[0].Pt1(0).Distance(pieces[len(pieces)-1].Pt1(1)))
+
+	left := s.side(pieces, h, closed)
+	right := s.side(pieces, -h, closed).Reverse()
+	if closed {
+		return []path.Path{left, right}
+	}
+
+	last := pieces[len(pieces)-1]
+	out := append(left, s.cap(last.Pt1(1), d2.GetV1(last).V1(1), h)...)
+	out = append(out, right...)
+	first := pieces[0]
+	out = append(out, s.cap(first.Pt1(0), d2.GetV1(first).V1(0).Multiply(-1), h)...)
+	return []path.Path{out}
+}
+
+// Polygons returns the outline of the curve as polygons by flattening the
+// paths returned by Stroke.
+func (s Stroker) Polygons(c d2.Pt1) []polygon.Polygon {
+	paths := s.Stroke(c)
+	out := make([]polygon.Polygon, len(paths))
+	tol := s.tolerance()
+	for i, p := range paths {
+		var pts []d2.Pt
+		for _, seg := range p {
+			f := flatten.Pt1(seg, tol)
+			if len(pts) > 0 && len(f) > 0 {
+				f = f[1:]
+			}
+			pts = append(pts, f...)
+		}
+		if ln := len(pts); ln > 1 && cmprZero(pts[0].Distance(pts[ln-1])) {
+			pts = pts[:ln-1]
+		}
+		out[i] = polygon.Polygon(pts)
+	}
+	return out
+}
+
+func cmprZero(d float64) bool {
+	return d < 1e-9
+}
+
+// decompose breaks a curve into the pieces that are joined.
+func decompose(c d2.Pt1) []d2.Pt1V1 {
+	switch t := c.(type) {
+	case path.Path:
+		return []d2.Pt1V1(t)
+	case line.Segments:
+		return segmentLines(t, false)
+	case polygon.Polygon:
+		return segmentLines(line.Segments(t), true)
+	case d2.Pt1V1:
+		return []d2.Pt1V1{t}
+	}
+	return []d2.Pt1V1{d2.V1Wrapper{P: c}}
+}
+
+func segmentLines(s line.Segments, closed bool) []d2.Pt1V1 {
+	var out []d2.Pt1V1
+	ln := len(s)
+	end := ln - 1
+	if closed {
+		end = ln
+	}
+	for i := 0; i < end; i++ {
+		a, b := s[i], s[(i+1)%ln]
+		if a != b {
+			out = append(out, line.New(a, b))
+		}
+	}
+	return out
+}
+
+// side offsets each piece by h and joins them.
+func (s Stroker) side(pieces []d2.Pt1V1, h float64, closed bool) path.Path {
+	ln := len(pieces)
+	offs := make([]path.Path, ln)
+	for i, p := range pieces {
+		offs[i] = s.offset(p, h)
+	}
+	// joins[i] connects piece i-1 to piece i. Joining can trim the offset
+	// pieces so all the joins are found before the side is assembled.
+	joins := make([]path.Path, ln)
+	for i := 1; i < ln; i++ {
+		joins[i] = s.join(pieces[i-1], pieces[i], offs[i-1], offs[i], h)
+	}
+	if closed {
+		joins[0] = s.join(pieces[ln-1], pieces[0], offs[ln-1], offs[0], h)
+	}
+
+	var out path.Path
+	for i, o := range offs {
+		if i > 0 {
+			out = append(out, joins[i]...)
+		}
+		out = append(out, o...)
+	}
+	return append(out, joins[0]...)
+}
+
+func (s Stroker) offset(p d2.Pt1V1, h float64) path.Path {
+	switch t := p.(type) {
+	case line.Line:
+		return path.Path{Line(t, h)}
+	case *ellipsearc.EllipseArc:
+		return EllipseArc(t, h, s.tolerance())
+	}
+	bs := Pt1V1(p, h, s.tolerance())
+	out := make(path.Path, len(bs))
+	for i, b := range bs {
+		out[i] = b
+	}
+	return out
+}
+
+// join connects the offset of the end of a to the offset of the start of b.
+// On the inside of a corner between two lines, the offset lines are trimmed to
+// meet instead.
+func (s Stroker) join(a, b d2.Pt1V1, aOff, bOff path.Path, h float64) path.Path {
+	pt := a.Pt1(1)
+	ta, tb := d2.GetV1(a).V1(1), d2.GetV1(b).V1(0)
+	na, nb := unitNormal(ta), unitNormal(tb)
+	p0, p1 := pt.Add(na.Multiply(h)), pt.Add(nb.Multiply(h))
+	if cmprZero(p0.Distance(p1)) {
+		return nil
+	}
+
+	cross := ta.Cross(tb)
+	if cross*h > 0 {
+		if trim(aOff, bOff) {
+			return nil
+		}
+		// Going through the vertex keeps the winding correct for the nonzero
+		// fill rule.
+		return path.Path{line.New(p0, pt), line.New(pt, p1)}
+	}
+
+	switch s.Join {
+	case RoundJoin:
+		return path.Path{arc(pt, na.Multiply(h), nb.Multiply(h))}
+	case MiterJoin:
+		sum := na.Add(nb)
+		if m2 := sum.Mag2(); m2 > 0 && 2/math.Sqrt(m2) <= s.miterLimit() {
+			m := pt.Add(sum.Multiply(2 * h / m2))
+			return path.Path{line.New(p0, m), line.New(m, p1)}
+		}
+	case SquareJoin:
+		v0, v1 := squareCorner(ta, tb, na.Multiply(h), nb.Multiply(h))
+		q0, q1 := pt.Add(v0), pt.Add(v1)
+		return path.Path{line.New(p0, q0), line.New(q0, q1), line.New(q1, p1)}
+	}
+	return path.Path{line.New(p0, p1)}
+}
+
+// squareCorner returns the corners of a square join relative to the vertex. ta
+// and tb are the tangents into and out of the vertex and oa and ob are the
+// offsets of the vertex along each edge. The corners lie at the offset distance
+// from the vertex along the bisector of oa and ob.
+func squareCorner(ta, tb, oa, ob d2.V) (d2.V, d2.V) {
+	h := oa.Mag()
+	ta, tb = ta.Multiply(1/ta.Mag()), tb.Multiply(1/tb.Mag())
+	u := oa.Add(ob)
+	if m := u.Mag(); m > 1e-12*h {
+		u = u.Multiply(1 / m)
+	} else {
+		u = ta
+	}
+	s0 := (h - oa.Dot(u)) / ta.Dot(u)
+	s1 := (h - ob.Dot(u)) / -tb.Dot(u)
+	return oa.Add(ta.Multiply(s0)), ob.Add(tb.Multiply(-s1))
+}
+
+// trim the last segment of a and the first segment of b to the point where
+// they intersect if both are lines.
+func trim(a, b path.Path) bool {
+	la, ok := a[len(a)-1].(line.Line)
+	if !ok {
+		return false
+	}
+	lb, ok := b[0].(line.Line)
+	if !ok {
+		return false
+	}
+	ta, tb, ok := la.Intersection(lb)
+	if !ok || ta <= 0 || ta > 1 || tb < 0 || tb >= 1 {
+		return false
+	}
+	m := la.Pt1(ta)
+	a[len(a)-1] = line.New(la.T0, m)
+	b[0] = line.New(m, lb.Pt1(1))
+	return true
+}
+
+// cap connects the left side to the right side at the end of a curve with
+// tangent v.
+func (s Stroker) cap(pt d2.Pt, v d2.V, h float64) path.Path {
+	n := unitNormal(v).Multiply(h)
+	p0, p1 := pt.Add(n), pt.Add(n.Multiply(-1))
+	switch s.Cap {
+	case RoundCap:
+		return path.Path{arc(pt, n, n.Multiply(-1))}
+	case SquareCap:
+		ext := d2.V{n.Y, -n.X}
+		e0, e1 := p0.Add(ext), p1.Add(ext)
+		return path.Path{line.New(p0, e0), line.New(e0, e1), line.New(e1, p1)}
+	}
+	return path.Path{line.New(p0, p1)}
+}
+
+// arc returns a circular arc around center from center+v0 to center+v1. A half
+// circle always proceeds clockwise.
+func arc(center d2.Pt, v0, v1 d2.V) *ellipsearc.EllipseArc {
+	r := v0.Mag()
+	l := math.Atan2(v0.Cross(v1), v0.Dot(v1))
+	if l >= math.Pi-1e-12 {
+		l = -math.Pi
+	}
+	e := ellipsearc.NewAxis(center, r, r, 0)
+	e.Start = float64(v0.Angle())
+	e.Length = l
+	return e
+}
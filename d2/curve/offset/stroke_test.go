@@ -0,0 +1,92 @@
+package offset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/curve/path"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrokeLine(t *testing.T) {
+	l := line.New(d2.Pt{0, 0}, d2.Pt{10, 0})
+	s := Stroker{Width: 2, Tolerance: 1e-5}
+
+	ps := s.Polygons(l)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 20.0, ps[0].Area())
+	}
+
+	s.Cap = SquareCap
+	ps = s.Polygons(l)
+	geomtest.Equal(t, 24.0, ps[0].Area())
+	assert.True(t, ps[0].Contains(d2.Pt{-0.5, 0.5}))
+
+	s.Cap = RoundCap
+	ps = s.Polygons(l)
+	assert.InDelta(t, 20+math.Pi, ps[0].Area(), 1e-3)
+
+	p := s.Stroke(l)[0]
+	assert.True(t, p.Closed(1e-9))
+	assert.True(t, p.G0(1e-9))
+	assert.True(t, p.Contains(d2.Pt{10.5, 0}))
+	assert.False(t, p.Contains(d2.Pt{5, 1.5}))
+}
+
+func TestStrokeJoins(t *testing.T) {
+	sq := polygon.Polygon{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	s := Stroker{Width: 2, Tolerance: 1e-5}
+
+	// sq is counter-clockwise so the left side is the inside
+	ps := s.Polygons(sq)
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, 4.0, ps[0].Area())
+		geomtest.Equal(t, 36.0, ps[1].Area())
+	}
+
+	s.Join = BevelJoin
+	ps = s.Polygons(sq)
+	geomtest.Equal(t, 4.0, ps[0].Area())
+	geomtest.Equal(t, 34.0, ps[1].Area())
+
+	s.Join = RoundJoin
+	ps = s.Polygons(sq)
+	geomtest.Equal(t, 4.0, ps[0].Area())
+	assert.InDelta(t, 32+math.Pi, ps[1].Area(), 1e-3)
+
+	// each corner loses a right triangle with a height of sqrt(2)-1
+	s.Join = SquareJoin
+	ps = s.Polygons(sq)
+	geomtest.Equal(t, 4.0, ps[0].Area())
+	cut := (math.Sqrt2 - 1) * (math.Sqrt2 - 1)
+	geomtest.Equal(t, 36-4*cut, ps[1].Area())
+
+	// a miter that is too long becomes a bevel
+	s = Stroker{Width: 2, MiterLimit: 1.1}
+	ps = s.Polygons(sq)
+	geomtest.Equal(t, 34.0, ps[1].Area())
+}
+
+func TestStrokeCurve(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+	p := path.New(line.New(d2.Pt{-5, 0}, d2.Pt{0, 0}), b)
+	s := Stroker{Width: 1, Join: RoundJoin, Cap: RoundCap, Tolerance: 1e-4}
+	out := s.Stroke(p)
+	if assert.Len(t, out, 1) {
+		o := out[0]
+		assert.True(t, o.Closed(1e-9))
+		assert.True(t, o.G0(1e-6))
+		for i := 0.05; i < 1; i += 0.1 {
+			pt := b.Pt1(i)
+			assert.True(t, o.Contains(pt))
+			n := d2.Normal(b, i)
+			assert.True(t, o.Contains(pt.Add(n.Multiply(0.45))))
+			assert.False(t, o.Contains(pt.Add(n.Multiply(0.55))))
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Package path joins curves of different types into a single curve.
+package path
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/calc/cmpr"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/intersect"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+)
+
+// Steps is the number of points each segment is sampled at when there is not
+// an exact method for a computation.
+var Steps = 50
+
+// Path is a sequence of curves joined end to end. Like line.Segments, each
+// segment is weighted equally regardless of length.
+type Path []d2.Pt1V1
+
+// New creates a Path from the given segments.
+func New(segments ...d2.Pt1V1) Path {
+	return Path(segments)
+}
+
+// Local converts a parametric value relative to the Path to the index of a
+// segment and a parametric value relative to that segment.
+func (p Path) Local(t0 float64) (int, float64) {
+	ln := len(p)
+	ts := t0 * float64(ln)
+	idx := int(math.Floor(ts))
+	if idx > ln-1 {
+		idx = ln - 1
+	} else if idx < 0 {
+		idx = 0
+	}
+	return idx, ts - float64(idx)
+}
+
+// Global converts a parametric value relative to the segment at idx to a
+// parametric value relative to the Path.
+func (p Path) Global(idx int, t0 float64) float64 {
+	return (float64(idx) + t0) / float64(len(p))
+}
+
+// Pt1 fulfills d2.Pt1.
+func (p Path) Pt1(t0 float64) d2.Pt {
+	if len(p) == 0 {
+		return d2.Pt{}
+	}
+	idx, t := p.Local(t0)
+	return p[idx].Pt1(t)
+}
+
+// V1 fulfills d2.V1.
+func (p Path) V1(t0 float64) d2.V {
+	if len(p) == 0 {
+		return d2.V{}
+	}
+	idx, t := p.Local(t0)
+	return p[idx].V1(t).Multiply(float64(len(p)))
+}
+
+// L fulfills d2.Limiter.
+func (Path) L(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// VL fulfills d2.VLimiter.
+func (Path) VL(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// Start of the path.
+func (p Path) Start() d2.Pt {
+	return p[0].Pt1(0)
+}
+
+// End of the path.
+func (p Path) End() d2.Pt {
+	return p[len(p)-1].Pt1(1)
+}
+
+// Closed returns true if the end of the path is within the tolerance of the
+// start.
+func (p Path) Closed(tol cmpr.Tolerance) bool {
+	if len(p) == 0 {
+		return false
+	}
+	v := p.End().Subtract(p.Start())
+	return tol.Zero(v.X) && tol.Zero(v.Y)
+}
+
+// joints returns the number of joints to check. A closed path has a joint
+// between the last and first segment.
+func (p Path) joints(tol cmpr.Tolerance) int {
+	if p.Closed(tol) {
+		return len(p)
+	}
+	return len(p) - 1
+}
+
+// G0 returns true if the end of each segment is within the tolerance of the
+// start of the next.
+func (p Path) G0(tol cmpr.Tolerance) bool {
+	for i := 0; i < len(p)-1; i++ {
+		v := p[i].Pt1(1).Subtract(p[i+1].Pt1(0))
+		if !tol.Zero(v.X) || !tol.Zero(v.Y) {
+			return false
+		}
+	}
+	return true
+}
+
+// G1 returns true if the path is G0 and the tangent direction is continuous
+// at each joint. The magnitude of the tangents does not need to match.
+func (p Path) G1(tol cmpr.Tolerance) bool {
+	if !p.G0(tol) {
+		return false
+	}
+	ln := len(p)
+	for i, n := 0, p.joints(tol); i < n; i++ {
+		v0, v1 := p[i].V1(1), p[(i+1)%ln].V1(0)
+		m0, m1 := v0.Mag(), v1.Mag()
+		if m0 == 0 || m1 == 0 {
+			return false
+		}
+		v0, v1 = v0.Multiply(1/m0), v1.Multiply(1/m1)
+		if !tol.Zero(v0.Cross(v1)) || v0.Dot(v1) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// crossing is an intersection with a line relative to both the line and a
+// segment.
+type crossing struct {
+	lineT float64
+	idx   int
+	segT  float64
+}
+
+// snap is used to treat values very close to the ends of a segment as the end.
+const snap = cmpr.Tolerance(1e-9)
+
+// crossings finds where l crosses the path. Each segment is treated as the
+// range [0,1) except the last segment of an open path, which includes 1.
+func (p Path) crossings(l line.Line, fn func(crossing) bool) {
+	ln := len(p)
+	closed := p.Closed(intersect.Tolerance)
+	for i, s := range p {
+		inRange := func(t float64) bool {
+			if snap.Zero(t) {
+				t = 0
+			} else if snap.Equal(t, 1) {
+				t = 1
+			}
+			return t >= 0 && (t < 1 || (t == 1 && !closed && i == ln-1))
+		}
+		if sl, ok := s.(line.Line); ok {
+			t0, t1, ok := l.Intersection(sl)
+			if ok && inRange(t1) && fn(crossing{t0, i, t1}) {
+				return
+			}
+			continue
+		}
+		var found []float64
+		prev := s.Pt1(0)
+		for j := 1; j <= Steps; j++ {
+			cur := s.Pt1(float64(j) / float64(Steps))
+			t0, t1, ok := l.Intersection(line.New(prev, cur))
+			prev = cur
+			if !ok || t1 < 0 || t1 > 1 {
+				continue
+			}
+			t0, t1, ok = intersect.Newton(l, s, t0, (float64(j-1)+t1)/float64(Steps))
+			if !ok || !inRange(t1) || contains(found, t1) {
+				continue
+			}
+			found = append(found, t1)
+			if fn(crossing{t0, i, t1}) {
+				return
+			}
+		}
+	}
+}
+
+func contains(ts []float64, t float64) bool {
+	const dup = cmpr.Tolerance(1e-7)
+	for _, t2 := range ts {
+		if dup.Equal(t, t2) {
+			return true
+		}
+	}
+	return false
+}
+
+// LineIntersections fulfills line.Intersector.
+func (p Path) LineIntersections(l line.Line, buf []float64) []float64 {
+	max := len(buf)
+	buf = buf[:0]
+	p.crossings(l, func(c crossing) bool {
+		buf = append(buf, c.lineT)
+		return max > 0 && len(buf) == max
+	})
+	return buf
+}
+
+// PathIntersections returns the intersections with the line relative to the
+// Path.
+func (p Path) PathIntersections(l line.Line, buf []float64) []float64 {
+	max := len(buf)
+	buf = buf[:0]
+	p.crossings(l, func(c crossing) bool {
+		buf = append(buf, p.Global(c.idx, c.segT))
+		return max > 0 && len(buf) == max
+	})
+	return buf
+}
+
+// ray is slightly skewed so that it is unlikely to pass exactly through a
+// joint.
+var ray = d2.V{1, 1.234567e-5}
+
+// Contains fulfills shape.Container using the winding number. If the path is
+// not closed, it is treated as if a line connects the end to the start.
+func (p Path) Contains(pt d2.Pt) bool {
+	if len(p) == 0 {
+		return false
+	}
+	l := line.Line{T0: pt, D: ray}
+	windings := 0
+	count := func(d d2.V) {
+		c := ray.Cross(d)
+		if c > 0 {
+			windings++
+		} else if c < 0 {
+			windings--
+		}
+	}
+	p.crossings(l, func(c crossing) bool {
+		if c.lineT > 0 {
+			count(p[c.idx].V1(c.segT))
+		}
+		return false
+	})
+	if !p.Closed(intersect.Tolerance) {
+		closing := line.New(p.End(), p.Start())
+		t0, t1, ok := l.Intersection(closing)
+		if ok && t0 > 0 && t1 >= 0 && t1 < 1 {
+			count(closing.D)
+		}
+	}
+	return windings != 0
+}
+
+// ConvexHull fulfills shape.ConvexHuller. Segments that fulfill
+// shape.ConvexHuller contribute their hulls and other curves are sampled.
+func (p Path) ConvexHull() []d2.Pt {
+	var pts []d2.Pt
+	for _, s := range p {
+		switch c := s.(type) {
+		case polygon.ConvexHuller:
+			pts = append(pts, c.ConvexHull()...)
+		case line.Line:
+			pts = append(pts, c.Pt1(0), c.Pt1(1))
+		default:
+			for j := 0; j <= Steps; j++ {
+				pts = append(pts, s.Pt1(float64(j)/float64(Steps)))
+			}
+		}
+	}
+	return polygon.ConvexHull(pts...)
+}
+
+// Segment returns the Path tracing the range [t0, t1]. Each segment is weighted
+// equally so the parameterization is only the same as the original if t0 and t1
+// fall on the joints between segments.
+func (p Path) Segment(t0, t1 float64) Path {
+	if len(p) == 0 {
+		return nil
+	}
+	if t1 < t0 {
+		return p.Segment(t1, t0).Reverse()
+	}
+	i0, lt0 := p.Local(t0)
+	i1, lt1 := p.Local(t1)
+	if lt1 == 0 && i1 > i0 {
+		i1, lt1 = i1-1, 1
+	}
+	if i0 == i1 {
+		return Path{pt1V1(d2.Segment(p[i0], lt0, lt1))}
+	}
+	out := make(Path, 0, i1-i0+1)
+	out = append(out, pt1V1(d2.Segment(p[i0], lt0, 1)))
+	out = append(out, p[i0+1:i1]...)
+	return append(out, pt1V1(d2.Segment(p[i1], 0, lt1)))
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (p Path) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return p.Segment(t0, t1)
+}
+
+// Reverse returns the Path tracing the segments in the opposite order with each
+// segment reversed.
+func (p Path) Reverse() Path {
+	out := make(Path, len(p))
+	for i, s := range p {
+		out[len(p)-1-i] = pt1V1(d2.Reverse(s))
+	}
+	return out
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (p Path) Pt1Reverse() d2.Pt1 {
+	return p.Reverse()
+}
+
+// T applies the transform to each segment.
+func (p Path) T(t *d2.T) Path {
+	out := make(Path, len(p))
+	for i, s := range p {
+		out[i] = pt1V1(d2.Transform(s, t))
+	}
+	return out
+}
+
+// Pt1T fulfills d2.Transformer.
+func (p Path) Pt1T(t *d2.T) d2.Pt1 {
+	return p.T(t)
+}
+
+// pt1V1 upgrades the result of one of the generic curve operations back to a
+// Pt1V1.
+func pt1V1(c d2.Pt1) d2.Pt1V1 {
+	if pv, ok := c.(d2.Pt1V1); ok {
+		return pv
+	}
+	return d2.V1Wrapper{P: c}
+}
@@ -0,0 +1,145 @@
+package path
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/boxmodel"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func dShape() Path {
+	return New(
+		line.New(d2.Pt{0, 0}, d2.Pt{0, 2}),
+		bezier.Bezier{{0, 2}, {2, 2}, {2, 0}, {0, 0}},
+	)
+}
+
+func TestPt1V1(t *testing.T) {
+	p := dShape()
+	geomtest.Equal(t, d2.Pt{0, 0}, p.Pt1(0))
+	geomtest.Equal(t, d2.Pt{0, 1}, p.Pt1(0.25))
+	geomtest.Equal(t, d2.Pt{0, 2}, p.Pt1(0.5))
+	geomtest.Equal(t, d2.Pt{0, 0}, p.Pt1(1))
+	geomtest.Equal(t, d2.V{0, 4}, p.V1(0.25))
+
+	idx, t0 := p.Local(0.75)
+	assert.Equal(t, 1, idx)
+	geomtest.Equal(t, 0.5, t0)
+	geomtest.Equal(t, 0.75, p.Global(idx, t0))
+
+	geomtest.EqualInDelta(t, d2.AssertV1{}, New(
+		bezier.Bezier{{0, 0}, {1, 1}, {2, 0}},
+		bezier.Bezier{{2, 0}, {3, -1}, {4, 0}},
+	), 1e-6)
+}
+
+func TestContinuity(t *testing.T) {
+	p := dShape()
+	assert.True(t, p.Closed(1e-10))
+	assert.True(t, p.G0(1e-10))
+	assert.False(t, p.G1(1e-10))
+
+	smooth := New(
+		bezier.Bezier{{0, 0}, {1, 1}, {2, 0}},
+		bezier.Bezier{{2, 0}, {3, -1}, {4, 0}},
+	)
+	assert.False(t, smooth.Closed(1e-10))
+	assert.True(t, smooth.G0(1e-10))
+	assert.True(t, smooth.G1(1e-10))
+
+	broken := New(
+		line.New(d2.Pt{0, 0}, d2.Pt{1, 0}),
+		line.New(d2.Pt{1, 1}, d2.Pt{2, 1}),
+	)
+	assert.False(t, broken.G0(1e-10))
+	assert.False(t, broken.G1(1e-10))
+}
+
+func TestShape(t *testing.T) {
+	var s shape.Shape = dShape()
+
+	assert.True(t, s.Contains(d2.Pt{0.5, 1}))
+	assert.True(t, s.Contains(d2.Pt{1.4, 1}))
+	assert.False(t, s.Contains(d2.Pt{-1, 1}))
+	assert.False(t, s.Contains(d2.Pt{1.6, 1}))
+	assert.False(t, s.Contains(d2.Pt{0.5, 3}))
+
+	l := line.New(d2.Pt{-1, 1}, d2.Pt{3, 1})
+	is := s.LineIntersections(l, nil)
+	if assert.Len(t, is, 2) {
+		geomtest.Equal(t, 0.25, is[0])
+		geomtest.Equal(t, d2.Pt{1.5, 1}, l.Pt1(is[1]))
+	}
+	assert.Len(t, s.LineIntersections(l, []float64{0}), 1)
+
+	pis := dShape().PathIntersections(l, nil)
+	if assert.Len(t, pis, 2) {
+		geomtest.Equal(t, 0.25, pis[0])
+		geomtest.Equal(t, 0.75, pis[1])
+	}
+
+	geomtest.Equal(t, polygon.AssertConvexHuller{{0, 0}, {0, 2}, {1.5, 1}}, s)
+}
+
+func TestArcPath(t *testing.T) {
+	// A half circle closed by a line.
+	e := ellipsearc.New(d2.Pt{0, 0}, d2.Pt{0, 0}, 1)
+	e.Length = math.Pi
+	p := New(e, line.New(d2.Pt{-1, 0}, d2.Pt{1, 0}))
+	assert.True(t, p.Closed(1e-10))
+	assert.True(t, p.Contains(d2.Pt{0, 0.5}))
+	assert.False(t, p.Contains(d2.Pt{0, -0.5}))
+	assert.False(t, p.Contains(d2.Pt{0.9, 0.9}))
+
+	is := p.LineIntersections(line.New(d2.Pt{0, -1}, d2.Pt{0, 2}), nil)
+	assert.Len(t, is, 2)
+
+	open := New(e)
+	assert.True(t, open.Contains(d2.Pt{0, 0.5}))
+	assert.False(t, open.Contains(d2.Pt{0, -0.5}))
+}
+
+func TestSegmentReverseT(t *testing.T) {
+	p := dShape()
+	s := p.Segment(0.25, 0.75)
+	assert.Len(t, s, 2)
+	geomtest.Equal(t, d2.Pt{0, 1}, s.Pt1(0))
+	geomtest.Equal(t, p.Pt1(0.75), s.Pt1(1))
+	geomtest.Equal(t, p.Pt1(0.625), s.Pt1(0.75))
+
+	rs := p.Segment(0.75, 0.25)
+	geomtest.Equal(t, p.Pt1(0.75), rs.Pt1(0))
+	geomtest.Equal(t, d2.Pt{0, 1}, rs.Pt1(1))
+
+	r := p.Reverse()
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	pt := p.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, p.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(p.Pt1(i)), pt.Pt1(i))
+	}
+}
+
+func TestBezierChainBoxModel(t *testing.T) {
+	// A circle of radius 100 approximated with cubic Beziers.
+	k := 100 * 0.5522847498
+	p := New(
+		bezier.Bezier{{100, 0}, {100, k}, {k, 100}, {0, 100}},
+		bezier.Bezier{{0, 100}, {-k, 100}, {-100, k}, {-100, 0}},
+		bezier.Bezier{{-100, 0}, {-100, -k}, {-k, -100}, {0, -100}},
+		bezier.Bezier{{0, -100}, {k, -100}, {100, -k}, {100, 0}},
+	)
+	geomtest.Equal(t, polygon.AssertConvexHuller{{100, 0}, {0, 100}, {-100, 0}, {0, -100}}, p)
+
+	b := boxmodel.New(p, 10)
+	assert.InDelta(t, math.Pi*100*100, b.Area(), 100)
+	geomtest.EqualInDelta(t, d2.Pt{0, 0}, b.Centroid(), 0.5)
+}
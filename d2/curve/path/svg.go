@@ -0,0 +1,485 @@
+package path
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/adamcolton/geom/angle"
+	"github.com/adamcolton/geom/calc/cmpr"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// ErrParse is returned when SVG path data cannot be parsed.
+type ErrParse struct {
+	Pos int
+	Msg string
+}
+
+// Error fulfills error.
+func (e ErrParse) Error() string {
+	return fmt.Sprintf("svg path: %s at %d", e.Msg, e.Pos)
+}
+
+// ParseSVG parses the d attribute of an SVG path element. Each subpath started
+// by a move command is returned as a separate Path. Lines are returned as
+// line.Line, quadratic and cubic curves as bezier.Bezier and arcs as
+// ellipsearc.EllipseArc.
+func ParseSVG(d string) ([]Path, error) {
+	p := &svgParser{s: d}
+	return p.parse()
+}
+
+type svgParser struct {
+	s   string
+	pos int
+
+	out       []Path
+	cur       Path
+	pt, start d2.Pt
+	ctrl      d2.Pt
+	prevCmd   byte
+}
+
+func (p *svgParser) err(msg string) error {
+	return ErrParse{Pos: p.pos, Msg: msg}
+}
+
+func (p *svgParser) skip() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// more returns true if the next token is a number.
+func (p *svgParser) more() bool {
+	p.skip()
+	if p.pos >= len(p.s) {
+		return false
+	}
+	c := p.s[p.pos]
+	return c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9')
+}
+
+func (p *svgParser) number() (float64, error) {
+	p.skip()
+	start := p.pos
+	i := p.pos
+	if i < len(p.s) && (p.s[i] == '-' || p.s[i] == '+') {
+		i++
+	}
+	digits := func() {
+		for i < len(p.s) && p.s[i] >= '0' && p.s[i] <= '9' {
+			i++
+		}
+	}
+	digits()
+	if i < len(p.s) && p.s[i] == '.' {
+		i++
+		digits()
+	}
+	if i < len(p.s) && (p.s[i] == 'e' || p.s[i] == 'E') {
+		j := i + 1
+		if j < len(p.s) && (p.s[j] == '-' || p.s[j] == '+') {
+			j++
+		}
+		if j < len(p.s) && p.s[j] >= '0' && p.s[j] <= '9' {
+			i = j
+			digits()
+		}
+	}
+	f, err := strconv.ParseFloat(p.s[start:i], 64)
+	if err != nil {
+		return 0, p.err("expected number")
+	}
+	p.pos = i
+	return f, nil
+}
+
+func (p *svgParser) flag() (bool, error) {
+	p.skip()
+	if p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '0':
+			p.pos++
+			return false, nil
+		case '1':
+			p.pos++
+			return true, nil
+		}
+	}
+	return false, p.err("expected flag")
+}
+
+func (p *svgParser) numbers(fs ...*float64) error {
+	for _, f := range fs {
+		var err error
+		if *f, err = p.number(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *svgParser) point(rel bool) (d2.Pt, error) {
+	var pt d2.Pt
+	if err := p.numbers(&pt.X, &pt.Y); err != nil {
+		return pt, err
+	}
+	if rel {
+		pt = p.pt.Add(pt.V())
+	}
+	return pt, nil
+}
+
+func (p *svgParser) flush() {
+	if len(p.cur) > 0 {
+		p.out = append(p.out, p.cur)
+	}
+	p.cur = nil
+}
+
+func (p *svgParser) lineTo(pt d2.Pt) {
+	p.cur = append(p.cur, line.New(p.pt, pt))
+	p.pt = pt
+}
+
+func (p *svgParser) parse() ([]Path, error) {
+	for {
+		p.skip()
+		if p.pos >= len(p.s) {
+			break
+		}
+		cmd := p.s[p.pos]
+		p.pos++
+		if p.prevCmd == 0 && cmd != 'M' && cmd != 'm' {
+			return nil, p.err("path must start with a move")
+		}
+		if err := p.command(cmd); err != nil {
+			return nil, err
+		}
+	}
+	p.flush()
+	return p.out, nil
+}
+
+func (p *svgParser) command(cmd byte) error {
+	rel := cmd >= 'a'
+	upper := cmd &^ 0x20
+	if upper == 'Z' {
+		if p.pt != p.start {
+			p.lineTo(p.start)
+		}
+		p.flush()
+		p.pt = p.start
+		p.prevCmd = upper
+		return nil
+	}
+
+	// Each command may be repeated with additional arguments.
+	for first := true; first || p.more(); first = false {
+		var err error
+		switch upper {
+		case 'M':
+			err = p.move(rel)
+		case 'L':
+			var pt d2.Pt
+			if pt, err = p.point(rel); err == nil {
+				p.lineTo(pt)
+			}
+		case 'H':
+			pt := p.pt
+			if pt.X, err = p.number(); err == nil {
+				if rel {
+					pt.X += p.pt.X
+				}
+				p.lineTo(pt)
+			}
+		case 'V':
+			pt := p.pt
+			if pt.Y, err = p.number(); err == nil {
+				if rel {
+					pt.Y += p.pt.Y
+				}
+				p.lineTo(pt)
+			}
+		case 'C', 'S':
+			err = p.cubic(rel, upper == 'S')
+		case 'Q', 'T':
+			err = p.quad(rel, upper == 'T')
+		case 'A':
+			err = p.arc(rel)
+		default:
+			return p.err(fmt.Sprintf("unknown command %q", cmd))
+		}
+		if err != nil {
+			return err
+		}
+		if upper == 'M' {
+			// subsequent pairs after a move are treated as lines
+			upper = 'L'
+			p.prevCmd = 'M'
+		} else {
+			p.prevCmd = upper
+		}
+	}
+	return nil
+}
+
+func (p *svgParser) move(rel bool) error {
+	pt, err := p.point(rel)
+	if err != nil {
+		return err
+	}
+	p.flush()
+	p.pt, p.start = pt, pt
+	return nil
+}
+
+// reflect returns the reflection of the previous control point if the
+// previous command matches one of cmds, otherwise it returns the current point.
+func (p *svgParser) reflect(cmds string) d2.Pt {
+	if strings.IndexByte(cmds, p.prevCmd) >= 0 {
+		return p.pt.Add(p.pt.Subtract(p.ctrl))
+	}
+	return p.pt
+}
+
+func (p *svgParser) cubic(rel, smooth bool) error {
+	var c1 d2.Pt
+	var err error
+	if smooth {
+		c1 = p.reflect("CS")
+	} else if c1, err = p.point(rel); err != nil {
+		return err
+	}
+	c2, err := p.point(rel)
+	if err != nil {
+		return err
+	}
+	end, err := p.point(rel)
+	if err != nil {
+		return err
+	}
+	p.cur = append(p.cur, bezier.Bezier{p.pt, c1, c2, end})
+	p.ctrl, p.pt = c2, end
+	return nil
+}
+
+func (p *svgParser) quad(rel, smooth bool) error {
+	var c d2.Pt
+	var err error
+	if smooth {
+		c = p.reflect("QT")
+	} else if c, err = p.point(rel); err != nil {
+		return err
+	}
+	end, err := p.point(rel)
+	if err != nil {
+		return err
+	}
+	p.cur = append(p.cur, bezier.Bezier{p.pt, c, end})
+	p.ctrl, p.pt = c, end
+	return nil
+}
+
+func (p *svgParser) arc(rel bool) error {
+	var rx, ry, rot float64
+	if err := p.numbers(&rx, &ry, &rot); err != nil {
+		return err
+	}
+	large, err := p.flag()
+	if err != nil {
+		return err
+	}
+	sweep, err := p.flag()
+	if err != nil {
+		return err
+	}
+	end, err := p.point(rel)
+	if err != nil {
+		return err
+	}
+	if end == p.pt {
+		return nil
+	}
+	if rx == 0 || ry == 0 {
+		p.lineTo(end)
+		return nil
+	}
+	p.cur = append(p.cur, EndpointArc(p.pt, end, rx, ry, angle.Deg(rot), large, sweep))
+	p.pt = end
+	return nil
+}
+
+// EndpointArc converts the SVG endpoint parameterization of an elliptic arc
+// to an EllipseArc. The radii are scaled up if they are too small to reach
+// from start to end.
+func EndpointArc(start, end d2.Pt, rx, ry float64, rot angle.Rad, large, sweep bool) *ellipsearc.EllipseArc {
+	// https://www.w3.org/TR/SVG/implnote.html#ArcConversionEndpointToCenter
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	s, c := rot.Sincos()
+	hx, hy := (start.X-end.X)/2, (start.Y-end.Y)/2
+	x1 := c*hx + s*hy
+	y1 := -s*hx + c*hy
+
+	if l := (x1*x1)/(rx*rx) + (y1*y1)/(ry*ry); l > 1 {
+		l = math.Sqrt(l)
+		rx, ry = rx*l, ry*l
+	}
+
+	rx2, ry2 := rx*rx, ry*ry
+	num := rx2*ry2 - rx2*y1*y1 - ry2*x1*x1
+	den := rx2*y1*y1 + ry2*x1*x1
+	coef := 0.0
+	if num > 0 && den > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if large == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+
+	center := d2.Pt{
+		X: c*cx1 - s*cy1 + (start.X+end.X)/2,
+		Y: s*cx1 + c*cy1 + (start.Y+end.Y)/2,
+	}
+
+	t1 := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	t2 := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx)
+	dt := t2 - t1
+	if sweep && dt < 0 {
+		dt += 2 * math.Pi
+	} else if !sweep && dt > 0 {
+		dt -= 2 * math.Pi
+	}
+
+	e := ellipsearc.NewAxis(center, rx, ry, rot)
+	if ry > rx {
+		// NewAxis rotated the major axis by a quarter turn.
+		t1 -= math.Pi / 2
+	}
+	e.Start, e.Length = t1, dt
+	return e
+}
+
+// SVG returns the path as SVG path data. Segments that do not have an exact
+// SVG representation are approximated by lines using Steps points.
+func (p Path) SVG() string {
+	if len(p) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("M")
+	writePts(&sb, p.Start())
+	closed := p.Closed(svgTolerance)
+	for i, s := range p {
+		if closed && i == len(p)-1 {
+			if _, ok := s.(line.Line); ok {
+				break
+			}
+		}
+		writeSegment(&sb, s)
+	}
+	if closed {
+		sb.WriteString(" Z")
+	}
+	return sb.String()
+}
+
+// FormatSVG joins multiple paths into a single SVG path data string.
+func FormatSVG(paths ...Path) string {
+	strs := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if s := p.SVG(); s != "" {
+			strs = append(strs, s)
+		}
+	}
+	return strings.Join(strs, " ")
+}
+
+var svgTolerance = cmpr.Tolerance(1e-9)
+
+func writePts(sb *strings.Builder, pts ...d2.Pt) {
+	for _, pt := range pts {
+		sb.WriteString(" ")
+		sb.WriteString(formatFloat(pt.X))
+		sb.WriteString(" ")
+		sb.WriteString(formatFloat(pt.Y))
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writeSegment(sb *strings.Builder, s d2.Pt1V1) {
+	switch c := s.(type) {
+	case line.Line:
+		sb.WriteString(" L")
+		writePts(sb, c.Pt1(1))
+		return
+	case bezier.Bezier:
+		switch len(c) {
+		case 2:
+			sb.WriteString(" L")
+			writePts(sb, c[1])
+			return
+		case 3:
+			sb.WriteString(" Q")
+			writePts(sb, c[1:]...)
+			return
+		case 4:
+			sb.WriteString(" C")
+			writePts(sb, c[1:]...)
+			return
+		}
+	case *ellipsearc.EllipseArc:
+		writeArc(sb, c)
+		return
+	}
+	sb.WriteString(" L")
+	for j := 1; j <= Steps; j++ {
+		writePts(sb, s.Pt1(float64(j)/float64(Steps)))
+	}
+}
+
+func writeArc(sb *strings.Builder, e *ellipsearc.EllipseArc) {
+	M, m := e.Axis()
+	a, _, _ := e.Angle()
+	// An SVG arc cannot describe a full ellipse, so long arcs are split.
+	n := 1
+	if math.Abs(e.Length) > math.Pi*1.5 {
+		n = 2
+	}
+	ln := math.Abs(e.Length) / float64(n)
+	large := "0"
+	if ln > math.Pi {
+		large = "1"
+	}
+	sweep := "0"
+	if e.Length > 0 {
+		sweep = "1"
+	}
+	for i := 1; i <= n; i++ {
+		sb.WriteString(" A ")
+		sb.WriteString(strings.Join([]string{
+			formatFloat(M),
+			formatFloat(m),
+			formatFloat(a.Deg()),
+			large,
+			sweep,
+		}, " "))
+		writePts(sb, e.Pt1(float64(i)/float64(n)))
+	}
+}
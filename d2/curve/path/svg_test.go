@@ -0,0 +1,123 @@
+package path
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+	"github.com/adamcolton/geom/d2/curve/ellipsearc"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSVGLines(t *testing.T) {
+	ps, err := ParseSVG("M10,10 h5 v5 H10 z m20 0 l1-1 1,1")
+	assert.NoError(t, err)
+	if !assert.Len(t, ps, 2) {
+		return
+	}
+	assert.Len(t, ps[0], 4)
+	assert.True(t, ps[0].Closed(1e-10))
+	geomtest.Equal(t, line.New(d2.Pt{10, 10}, d2.Pt{15, 10}), ps[0][0])
+	geomtest.Equal(t, line.New(d2.Pt{15, 10}, d2.Pt{15, 15}), ps[0][1])
+	geomtest.Equal(t, line.New(d2.Pt{15, 15}, d2.Pt{10, 15}), ps[0][2])
+	geomtest.Equal(t, line.New(d2.Pt{10, 15}, d2.Pt{10, 10}), ps[0][3])
+
+	assert.Len(t, ps[1], 2)
+	geomtest.Equal(t, line.New(d2.Pt{30, 10}, d2.Pt{31, 9}), ps[1][0])
+	geomtest.Equal(t, line.New(d2.Pt{31, 9}, d2.Pt{32, 10}), ps[1][1])
+
+	// Implicit lineto after moveto
+	ps, err = ParseSVG("M0 0 1 1 2 0")
+	assert.NoError(t, err)
+	assert.Len(t, ps[0], 2)
+}
+
+func TestParseSVGCurves(t *testing.T) {
+	ps, err := ParseSVG("M0 0C0 1 1 1 1 0S2-1 2 0Q3 1 4 0T6 0")
+	assert.NoError(t, err)
+	p := ps[0]
+	if !assert.Len(t, p, 4) {
+		return
+	}
+	assert.Equal(t, bezier.Bezier{{0, 0}, {0, 1}, {1, 1}, {1, 0}}, p[0])
+	assert.Equal(t, bezier.Bezier{{1, 0}, {1, -1}, {2, -1}, {2, 0}}, p[1])
+	assert.Equal(t, bezier.Bezier{{2, 0}, {3, 1}, {4, 0}}, p[2])
+	assert.Equal(t, bezier.Bezier{{4, 0}, {5, -1}, {6, 0}}, p[3])
+
+	ps, err = ParseSVG("m1 1c0 1 1 1 1 0q1 1 2 0")
+	assert.NoError(t, err)
+	assert.Equal(t, bezier.Bezier{{1, 1}, {1, 2}, {2, 2}, {2, 1}}, ps[0][0])
+	assert.Equal(t, bezier.Bezier{{2, 1}, {3, 2}, {4, 1}}, ps[0][1])
+}
+
+func TestParseSVGArc(t *testing.T) {
+	// Upper half of a unit circle, counter clockwise in a y-up space.
+	ps, err := ParseSVG("M1 0 A1 1 0 0 1 -1 0")
+	assert.NoError(t, err)
+	e := ps[0][0].(*ellipsearc.EllipseArc)
+	geomtest.Equal(t, d2.Pt{1, 0}, e.Pt1(0))
+	geomtest.Equal(t, d2.Pt{0, 1}, e.Pt1(0.5))
+	geomtest.Equal(t, d2.Pt{-1, 0}, e.Pt1(1))
+
+	// Same endpoints, opposite sweep.
+	ps, err = ParseSVG("M1 0 A1 1 0 0 0 -1 0")
+	assert.NoError(t, err)
+	e = ps[0][0].(*ellipsearc.EllipseArc)
+	geomtest.Equal(t, d2.Pt{0, -1}, e.Pt1(0.5))
+
+	// Packed flags, rotated ellipse with ry > rx and a large arc.
+	ps, err = ParseSVG("M0 0a1 2 30 1100 1")
+	assert.NoError(t, err)
+	e = ps[0][0].(*ellipsearc.EllipseArc)
+	geomtest.Equal(t, d2.Pt{0, 0}, e.Pt1(0))
+	geomtest.Equal(t, d2.Pt{0, 1}, e.Pt1(1))
+	assert.True(t, math.Abs(e.Length) > math.Pi)
+	M, m := e.Axis()
+	geomtest.Equal(t, 2.0, M)
+	geomtest.Equal(t, 1.0, m)
+
+	// Radii too small are scaled up.
+	e = EndpointArc(d2.Pt{-2, 0}, d2.Pt{2, 0}, 1, 1, 0, false, true)
+	geomtest.Equal(t, d2.Pt{0, 0}, e.Centroid())
+	M, _ = e.Axis()
+	geomtest.Equal(t, 2.0, M)
+}
+
+func TestParseSVGErrors(t *testing.T) {
+	for _, d := range []string{"L1 1", "M1", "M0 0 A1 1 0 2 0 1 1", "M0 0 X"} {
+		_, err := ParseSVG(d)
+		assert.Error(t, err, d)
+	}
+}
+
+func TestSVGRoundTrip(t *testing.T) {
+	for _, d := range []string{
+		"M10 10 L15 10 L15 15 L10 15 Z",
+		"M0 0 C0 1 1 1 1 0 Q3 1 4 0",
+		"M1 0 A 1 1 0 0 1 -1 0 L1 0 Z",
+		"M0 0 A 2 1 30 1 0 0 1",
+	} {
+		ps, err := ParseSVG(d)
+		assert.NoError(t, err)
+		s := FormatSVG(ps...)
+		ps2, err := ParseSVG(s)
+		assert.NoError(t, err)
+		if !assert.Len(t, ps2, len(ps)) {
+			continue
+		}
+		for i, p := range ps {
+			for j := 0.0; j <= 1.0; j += 0.05 {
+				geomtest.EqualInDelta(t, p.Pt1(j), ps2[i].Pt1(j), 1e-9, s)
+			}
+		}
+	}
+
+	assert.Equal(t, "M 10 10 L 15 10 L 15 15 Z", New(
+		line.New(d2.Pt{10, 10}, d2.Pt{15, 10}),
+		line.New(d2.Pt{15, 10}, d2.Pt{15, 15}),
+		line.New(d2.Pt{15, 15}, d2.Pt{10, 10}),
+	).SVG())
+}
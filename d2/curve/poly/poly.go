@@ -89,6 +89,40 @@ func (p Poly) V1(t0 float64) d2.V {
 	return p.V1c0().V1(t0)
 }
 
+// V2 returns the second derivative of p at t0.
+func (p Poly) V2(t0 float64) d2.V {
+	return p.V().V().V1(t0)
+}
+
+// Inflections fulfills d2.Inflectioner. The inflection points are the roots of
+// the cross product of the first and second derivatives in the range [0,1].
+func (p Poly) Inflections() []float64 {
+	dx, dy := p.X().D(), p.Y().D()
+	ddx, ddy := dx.D(), dy.D()
+	cross := dx.Multiply(ddy).Add(dy.Multiply(ddx).Scale(-1))
+	return cross.RootsIn(0, 1)
+}
+
+// Nearest returns the parametric value in the range [0,1] of the point on the
+// curve closest to pt and the distance to that point. The candidates are the
+// end points and the roots of (P(t)-pt)·P'(t).
+func (p Poly) Nearest(pt d2.Pt) (float64, float64) {
+	x := p.X().Add(poly1d.New(-pt.X))
+	y := p.Y().Add(poly1d.New(-pt.Y))
+	f := x.Multiply(x.D()).Add(y.Multiply(y.D()))
+
+	bestT, bestD := 0.0, p.Pt1(0).Distance(pt)
+	if d := p.Pt1(1).Distance(pt); d < bestD {
+		bestT, bestD = 1, d
+	}
+	for _, t := range f.RootsIn(0, 1) {
+		if d := p.Pt1(t).Distance(pt); d < bestD {
+			bestT, bestD = t, d
+		}
+	}
+	return bestT, bestD
+}
+
 // PolyLineIntersections returns the intersection points relative to the
 // Polynomial curve.
 func (p Poly) PolyLineIntersections(l line.Line, buf []float64) []float64 {
@@ -129,3 +163,58 @@ func (p Poly) LineIntersections(l line.Line, buf []float64) []float64 {
 	}
 	return ts
 }
+
+// Segment returns the polynomial describing the range [t0, t1] over the range
+// [0,1]. This is done by composing the polynomial with t0 + (t1-t0)*t.
+func (p Poly) Segment(t0, t1 float64) Poly {
+	ln := p.Len()
+	out := make(Slice, ln)
+	if ln == 0 {
+		return Poly{out}
+	}
+	// Horner's method with each step multiplying by (t0 + d*t).
+	d := t1 - t0
+	out[0] = p.Coefficient(ln - 1)
+	for i := ln - 2; i >= 0; i-- {
+		for j := ln - 1 - i; j > 0; j-- {
+			out[j] = out[j].Multiply(t0).Add(out[j-1].Multiply(d))
+		}
+		out[0] = out[0].Multiply(t0).Add(p.Coefficient(i))
+	}
+	return Poly{out}
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (p Poly) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return p.Segment(t0, t1)
+}
+
+// Reverse returns the polynomial traced from 1 to 0.
+func (p Poly) Reverse() Poly {
+	return p.Segment(1, 0)
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (p Poly) Pt1Reverse() d2.Pt1 {
+	return p.Reverse()
+}
+
+// T applies the transform to the polynomial. The translation only applies to
+// the constant coefficient.
+func (p Poly) T(t *d2.T) Poly {
+	ln := p.Len()
+	out := make(Slice, ln)
+	for i := range out {
+		if i == 0 {
+			out[i] = t.Pt(p.Coefficient(i).Pt()).V()
+		} else {
+			out[i] = t.Linear(p.Coefficient(i))
+		}
+	}
+	return Poly{out}
+}
+
+// Pt1T fulfills d2.Transformer.
+func (p Poly) Pt1T(t *d2.T) d2.Pt1 {
+	return p.T(t)
+}
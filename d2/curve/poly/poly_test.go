@@ -128,3 +128,72 @@ func TestLineIntersections(t *testing.T) {
 		})
 	}
 }
+
+func TestV2(t *testing.T) {
+	b := bezier.Bezier{{0, 0}, {0, 1}, {1, -1}, {1, 0}}
+	p := poly.NewBezier(b)
+	geomtest.Equal(t, d2.AssertV2{}, p)
+	for i := 0.0; i <= 1.0; i += 0.1 {
+		geomtest.Equal(t, b.V2(i), p.V2(i))
+	}
+}
+
+func TestInflections(t *testing.T) {
+	p := poly.NewBezier([]d2.Pt{{0, 0}, {0, 1}, {1, -1}, {1, 0}})
+	ts := p.Inflections()
+	if assert.Len(t, ts, 1) {
+		geomtest.Equal(t, 0.5, ts[0])
+		geomtest.Equal(t, 0.0, d2.Curvature(p, ts[0]))
+	}
+
+	// a parabola has no inflection points
+	assert.Len(t, poly.New(d2.V{0, 0}, d2.V{1, 0}, d2.V{0, 1}).Inflections(), 0)
+
+	// Roots misses the only root of the degree 6 cross product in [0,1]
+	p = poly.NewBezier([]d2.Pt{{2, 4}, {6, 8}, {4, 9}, {8, 5}, {3, 3}})
+	ts = p.Inflections()
+	if assert.Len(t, ts, 1) {
+		assert.InDelta(t, 0.1700, ts[0], 1e-4)
+		assert.InDelta(t, 0.0, d2.Curvature(p, ts[0]), 1e-9)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	p := poly.New(d2.V{0, 0}, d2.V{1, 0}, d2.V{0, 1})
+	tt, d := p.Nearest(d2.Pt{0.5, 0.25})
+	geomtest.Equal(t, 0.5, tt)
+	geomtest.Equal(t, 0.0, d)
+
+	// parabola y=x^2, closest point to (0, 2) in [0,1] is the end point
+	tt, d = p.Nearest(d2.Pt{0, 2})
+	geomtest.Equal(t, 1.0, tt)
+	geomtest.Equal(t, d2.Pt{1, 1}.Distance(d2.Pt{0, 2}), d)
+
+	tt, d = p.Nearest(d2.Pt{-1, 0})
+	geomtest.Equal(t, 0.0, tt)
+	geomtest.Equal(t, 1.0, d)
+
+	// Roots misses the root of the quintic that gives the closest point and
+	// returns the end point at a distance of 2.236
+	p = poly.NewBezier([]d2.Pt{{3, 0}, {4, 0}, {0, 6}, {10, 10}})
+	tt, d = p.Nearest(d2.Pt{8, 9})
+	assert.InDelta(t, 0.9245, tt, 1e-4)
+	assert.InDelta(t, 0.0717, d, 1e-4)
+}
+
+func TestSegmentReverseT(t *testing.T) {
+	p := poly.New(d2.V{1, 2}, d2.V{3, -1}, d2.V{-2, 4}, d2.V{1, 1})
+	s := p.Segment(0.2, 0.7)
+	r := p.Reverse()
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	pt := p.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, p.Pt1(0.2+i*0.5), s.Pt1(i))
+		geomtest.Equal(t, p.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(p.Pt1(i)), pt.Pt1(i))
+	}
+	geomtest.Equal(t, d2.AssertV1{}, s)
+
+	_, ok := d2.Segment(p, 0, 0.5).(poly.Poly)
+	assert.True(t, ok)
+}
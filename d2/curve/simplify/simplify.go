@@ -0,0 +1,364 @@
+// Package simplify reduces the number of points in line.Segments and
+// polygon.Polygon while keeping their shape.
+package simplify
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/flatten"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+)
+
+// Method is the algorithm used to choose the points to remove.
+type Method byte
+
+const (
+	// RDP is the Ramer-Douglas-Peucker algorithm. Tolerance is the maximum
+	// distance from a removed point to the simplified shape.
+	RDP Method = iota
+	// VW is the Visvalingam-Whyatt algorithm. Points are removed in order of
+	// the area of the triangle they form with their neighbors and Tolerance is
+	// the smallest area that is kept.
+	VW
+)
+
+// Simplifier removes points from line.Segments and polygon.Polygon.
+type Simplifier struct {
+	Method
+	Tolerance float64
+	// PreserveTopology prevents the simplified shape from intersecting itself
+	// if the original shape did not. This keeps points that would otherwise be
+	// removed.
+	PreserveTopology bool
+}
+
+// Segments returns a copy of ls with points removed. The end points are always
+// kept.
+func (s Simplifier) Segments(ls line.Segments) line.Segments {
+	if len(ls) < 3 {
+		return append(line.Segments(nil), ls...)
+	}
+	return line.Segments(s.simplify(ls, false))
+}
+
+// Polygon returns a copy of p with points removed. At least 3 points are
+// always kept.
+func (s Simplifier) Polygon(p polygon.Polygon) polygon.Polygon {
+	if len(p) < 4 {
+		return append(polygon.Polygon(nil), p...)
+	}
+	return polygon.Polygon(s.simplify(p, true))
+}
+
+func (s Simplifier) simplify(pts []d2.Pt, closed bool) []d2.Pt {
+	var keep []bool
+	if s.Method == VW {
+		keep = s.vw(pts, closed)
+	} else {
+		keep = s.rdp(pts, closed)
+	}
+	var out []d2.Pt
+	for i, pt := range pts {
+		if keep[i] {
+			out = append(out, pt)
+		}
+	}
+	return out
+}
+
+// rdp marks the points to keep. A closed shape is treated as two open chains
+// between the first point and the point furthest from it. When preserving
+// topology, untangle fixes any simplified segments that cross.
+func (s Simplifier) rdp(pts []d2.Pt, closed bool) []bool {
+	ln := len(pts)
+	if closed {
+		// the copy of the first point at the end closes the shape
+		pts = append(pts[:ln:ln], pts[0])
+	}
+	end := len(pts) - 1
+	keep := make([]bool, len(pts))
+	keep[0], keep[end] = true, true
+	if closed {
+		far, _ := furthest(pts, 0, end)
+		if far < 0 {
+			far = 1
+		}
+		keep[far] = true
+		rdp(pts, keep, 0, far, s.Tolerance)
+		rdp(pts, keep, far, end, s.Tolerance)
+		if count(keep) < 4 {
+			// 3 distinct points are needed for a polygon
+			f, d := furthest(pts, 0, far)
+			if f1, d1 := furthest(pts, far, end); d1 > d {
+				f = f1
+			}
+			if f >= 0 {
+				keep[f] = true
+			}
+		}
+	} else {
+		rdp(pts, keep, 0, end, s.Tolerance)
+	}
+
+	if s.PreserveTopology {
+		untangle(pts, keep)
+	}
+
+	if closed {
+		keep = keep[:ln]
+	}
+	return keep
+}
+
+func rdp(pts []d2.Pt, keep []bool, i, j int, tolerance float64) {
+	if far, d := furthest(pts, i, j); far >= 0 && d > tolerance {
+		keep[far] = true
+		rdp(pts, keep, i, far, tolerance)
+		rdp(pts, keep, far, j, tolerance)
+	}
+}
+
+// furthest returns the index of the point between i and j that is furthest
+// from the segment connecting them.
+func furthest(pts []d2.Pt, i, j int) (int, float64) {
+	far, fd := -1, -1.0
+	for k := i + 1; k < j; k++ {
+		if d := flatten.SegmentDistance(pts[i], pts[j], pts[k]); d > fd {
+			far, fd = k, d
+		}
+	}
+	return far, fd
+}
+
+func kept(keep []bool) []int {
+	var out []int
+	for i, k := range keep {
+		if k {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func count(keep []bool) int {
+	return len(kept(keep))
+}
+
+// untangle adds the furthest point back to any simplified segment that crosses
+// another until none cross. A segment that did not cross anything can only
+// start crossing a segment added since, so after the first pass only the new
+// segments are checked.
+func untangle(pts []d2.Pt, keep []bool) {
+	next := make([]int, len(pts))
+	g := newSegGrid(pts)
+	var check [][2]int
+	idx := kept(keep)
+	for k := 1; k < len(idx); k++ {
+		i, j := idx[k-1], idx[k]
+		next[i] = j
+		g.add(pts, i, j)
+		check = append(check, [2]int{i, j})
+	}
+	for len(check) > 0 {
+		bad := make(map[[2]int]bool)
+		for _, c := range check {
+			for _, s := range g.crossing(pts, keep, next, c[0], c[1]) {
+				bad[c], bad[s] = true, true
+			}
+		}
+		check = check[:0]
+		for s := range bad {
+			i, j := s[0], s[1]
+			f, _ := furthest(pts, i, j)
+			if f < 0 {
+				continue
+			}
+			keep[f] = true
+			next[i], next[f] = f, j
+			g.add(pts, i, f)
+			g.add(pts, f, j)
+			check = append(check, [2]int{i, f}, [2]int{f, j})
+		}
+	}
+}
+
+// crosses uses the same test as polygon.NonIntersecting, so segments that only
+// share an end point do not cross.
+func crosses(a, b line.Line) bool {
+	t0, t1, ok := a.Intersection(b)
+	return ok && t0 > 0 && t0 < 1 && t1 > 0 && t1 < 1
+}
+
+type vwItem struct {
+	area float64
+	idx  int
+	ver  int
+}
+
+type vwHeap []vwItem
+
+func (h vwHeap) Len() int            { return len(h) }
+func (h vwHeap) Less(i, j int) bool  { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *vwHeap) Push(x interface{}) { *h = append(*h, x.(vwItem)) }
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// vw marks the points to keep by repeatedly removing the point that forms the
+// smallest triangle with its neighbors.
+func (s Simplifier) vw(pts []d2.Pt, closed bool) []bool {
+	ln := len(pts)
+	prev, next := make([]int, ln), make([]int, ln)
+	ver := make([]int, ln)
+	keep := make([]bool, ln)
+	for i := range pts {
+		prev[i], next[i] = (i+ln-1)%ln, (i+1)%ln
+		keep[i] = true
+	}
+	fixed := func(i int) bool {
+		return !closed && (i == 0 || i == ln-1)
+	}
+	area := func(i int) float64 {
+		a, b, c := pts[prev[i]], pts[i], pts[next[i]]
+		return math.Abs(b.Subtract(a).Cross(c.Subtract(a))) / 2
+	}
+
+	h := &vwHeap{}
+	for i := range pts {
+		if !fixed(i) {
+			*h = append(*h, vwItem{area(i), i, 0})
+		}
+	}
+	heap.Init(h)
+
+	var g *segGrid
+	if s.PreserveTopology {
+		g = newSegGrid(pts)
+		for i := range pts {
+			if closed || i < ln-1 {
+				g.add(pts, i, next[i])
+			}
+		}
+	}
+
+	remaining, min := ln, 2
+	if closed {
+		min = 3
+	}
+	for h.Len() > 0 && remaining > min {
+		it := heap.Pop(h).(vwItem)
+		i := it.idx
+		if !keep[i] || it.ver != ver[i] {
+			continue
+		}
+		if it.area >= s.Tolerance {
+			break
+		}
+		if g != nil && len(g.crossing(pts, keep, next, prev[i], next[i])) > 0 {
+			continue
+		}
+		keep[i] = false
+		remaining--
+		p, n := prev[i], next[i]
+		next[p], prev[n] = n, p
+		if g != nil {
+			g.add(pts, p, n)
+		}
+		for _, j := range [2]int{p, n} {
+			if !fixed(j) {
+				ver[j]++
+				heap.Push(h, vwItem{area(j), j, ver[j]})
+			}
+		}
+	}
+	return keep
+}
+
+// segGrid holds the segments of the simplified shape in the cells covered by
+// their bounding boxes so that only the segments near a new segment are
+// checked. A segment is stored as the index of its start and end. A segment
+// has been replaced once its start is no longer kept or next no longer leads
+// from it to the same end.
+type segGrid struct {
+	min   d2.Pt
+	cell  float64
+	w, h  int
+	cells [][][2]int
+}
+
+func newSegGrid(pts []d2.Pt) *segGrid {
+	min, max := d2.MinMax(pts...)
+	v := max.Subtract(min)
+	cell := math.Max(v.X, v.Y) / math.Ceil(math.Sqrt(float64(len(pts))))
+	if cell == 0 {
+		cell = 1
+	}
+	w, h := int(v.X/cell)+1, int(v.Y/cell)+1
+	return &segGrid{
+		min:   min,
+		cell:  cell,
+		w:     w,
+		h:     h,
+		cells: make([][][2]int, w*h),
+	}
+}
+
+// span returns the range of cells covered by the bounding box of a and b.
+func (g *segGrid) span(a, b d2.Pt) (x0, y0, x1, y1 int) {
+	min, max := d2.MinMax(a, b)
+	clamp := func(f float64, n int) int {
+		i := int(f / g.cell)
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+	return clamp(min.X-g.min.X, g.w), clamp(min.Y-g.min.Y, g.h),
+		clamp(max.X-g.min.X, g.w), clamp(max.Y-g.min.Y, g.h)
+}
+
+func (g *segGrid) add(pts []d2.Pt, i, j int) {
+	x0, y0, x1, y1 := g.span(pts[i], pts[j])
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			c := y*g.w + x
+			g.cells[c] = append(g.cells[c], [2]int{i, j})
+		}
+	}
+}
+
+// crossing returns the remaining segments that cross the segment from a to b.
+// A segment may be returned more than once. Replaced segments are removed from
+// the cells that are checked.
+func (g *segGrid) crossing(pts []d2.Pt, keep []bool, next []int, a, b int) [][2]int {
+	var out [][2]int
+	l := line.New(pts[a], pts[b])
+	x0, y0, x1, y1 := g.span(pts[a], pts[b])
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			c := y*g.w + x
+			live := g.cells[c][:0]
+			for _, s := range g.cells[c] {
+				if !keep[s[0]] || next[s[0]] != s[1] {
+					continue
+				}
+				live = append(live, s)
+				if crosses(l, line.New(pts[s[0]], pts[s[1]])) {
+					out = append(out, s)
+				}
+			}
+			g.cells[c] = live
+		}
+	}
+	return out
+}
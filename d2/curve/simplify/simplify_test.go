@@ -0,0 +1,145 @@
+package simplify
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func sine(n int) line.Segments {
+	out := make(line.Segments, n)
+	for i := range out {
+		x := 4 * math.Pi * float64(i) / float64(n-1)
+		out[i] = d2.Pt{x, math.Sin(x)}
+	}
+	return out
+}
+
+func TestSegments(t *testing.T) {
+	ls := sine(1000)
+	for _, m := range []Method{RDP, VW} {
+		s := Simplifier{Method: m, Tolerance: 0.01}
+		if m == VW {
+			s.Tolerance = 1e-3
+		}
+		got := s.Segments(ls)
+		assert.True(t, len(got) < 100)
+		assert.Equal(t, ls[0], got[0])
+		assert.Equal(t, ls[len(ls)-1], got[len(got)-1])
+		if m == RDP {
+			for _, pt := range ls {
+				_, d := got.Nearest(pt)
+				assert.True(t, d <= s.Tolerance)
+			}
+		}
+	}
+
+	// near collinear points are removed
+	ls = make(line.Segments, 1000)
+	for i := range ls {
+		ls[i] = d2.Pt{float64(i), 1e-7 * float64(i%3)}
+	}
+	for _, m := range []Method{RDP, VW} {
+		got := Simplifier{Method: m, Tolerance: 1e-3}.Segments(ls)
+		geomtest.Equal(t, line.Segments{ls[0], ls[999]}, got)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	p := make(polygon.Polygon, 720)
+	for i := range p {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / float64(len(p)))
+		p[i] = d2.Pt{10 * c, 10 * s}
+	}
+	for _, m := range []Method{RDP, VW} {
+		got := Simplifier{Method: m, Tolerance: 0.1}.Polygon(p)
+		assert.True(t, len(got) < 40)
+		assert.InDelta(t, p.Area(), got.Area(), 4)
+	}
+
+	// at least a triangle is kept
+	for _, m := range []Method{RDP, VW} {
+		got := Simplifier{Method: m, Tolerance: 100}.Polygon(p)
+		assert.Len(t, got, 3)
+	}
+}
+
+func TestPreserveTopology(t *testing.T) {
+	// The bump on the bottom is smaller than the notch reaching down toward
+	// it. Removing the bump moves the bottom side across the notch.
+	p := polygon.Polygon{
+		{0, 0}, {4, 0}, {5, -0.4}, {6, 0}, {10, 0},
+		{10, 1}, {6, 1}, {5, -0.2}, {4, 1}, {0, 1},
+	}
+	assert.True(t, p.NonIntersecting())
+
+	tt := map[string]Simplifier{
+		"RDP": {Method: RDP, Tolerance: 0.6},
+		"VW":  {Method: VW, Tolerance: 1},
+	}
+	for n, s := range tt {
+		t.Run(n, func(t *testing.T) {
+			got := s.Polygon(p)
+			assert.False(t, got.NonIntersecting())
+
+			s.PreserveTopology = true
+			got = s.Polygon(p)
+			assert.True(t, got.NonIntersecting())
+			assert.True(t, len(got) < len(p))
+		})
+	}
+}
+
+// notches repeats the shape from TestPreserveTopology n times along a strip.
+// The depths vary so that the furthest point is not always the first one.
+func notches(n int) polygon.Polygon {
+	var bottom, top polygon.Polygon
+	for i := 0; i < n; i++ {
+		x, d := 10*float64(i), 0.05*math.Sin(float64(i))
+		bottom = append(bottom, d2.Pt{x + 4, 0}, d2.Pt{x + 5, -0.4 - d}, d2.Pt{x + 6, 0})
+		top = append(top, d2.Pt{x + 6, 1}, d2.Pt{x + 5, -0.2 + d}, d2.Pt{x + 4, 1})
+	}
+	end := 10 * float64(n)
+	p := append(polygon.Polygon{{0, 0}}, bottom...)
+	p = append(p, d2.Pt{end, 0}, d2.Pt{end, 1})
+	for i := len(top) - 3; i >= 0; i -= 3 {
+		p = append(p, top[i:i+3]...)
+	}
+	return append(p, d2.Pt{0, 1})
+}
+
+var topologySimplifiers = map[string]Simplifier{
+	"RDP": {Method: RDP, Tolerance: 0.6, PreserveTopology: true},
+	"VW":  {Method: VW, Tolerance: 1, PreserveTopology: true},
+}
+
+func TestPreserveTopologyLarge(t *testing.T) {
+	p := notches(3000)
+	assert.True(t, p.NonIntersecting())
+	for n, s := range topologySimplifiers {
+		t.Run(n, func(t *testing.T) {
+			got := s.Polygon(p)
+			assert.True(t, got.NonIntersecting())
+			assert.True(t, len(got) < len(p))
+
+			s.PreserveTopology = false
+			assert.False(t, s.Polygon(p).NonIntersecting())
+		})
+	}
+}
+
+func BenchmarkPreserveTopology(b *testing.B) {
+	p := notches(3000)
+	for n, s := range topologySimplifiers {
+		b.Run(n, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Polygon(p)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package spline
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+)
+
+// CatmullRom creates a centripetal Catmull-Rom spline through the points.
+// Centripetal parameterization avoids the cusps and self intersections that
+// the uniform parameterization can produce.
+func CatmullRom(pts []d2.Pt) Spline {
+	return CatmullRomAlpha(pts, 0.5)
+}
+
+// CatmullRomAlpha creates a Catmull-Rom spline through the points. An alpha of
+// 0 is uniform, 0.5 is centripetal and 1 is chordal. The ends are extended by
+// reflecting the second and second to last points.
+func CatmullRomAlpha(pts []d2.Pt, alpha float64) Spline {
+	ln := len(pts)
+	if ln < 2 {
+		return nil
+	}
+	ext := make([]d2.Pt, 0, ln+2)
+	ext = append(ext, pts[0].Add(pts[0].Subtract(pts[1])))
+	ext = append(ext, pts...)
+	ext = append(ext, pts[ln-1].Add(pts[ln-1].Subtract(pts[ln-2])))
+
+	// dt[i] is the knot interval between ext[i] and ext[i+1]
+	dt := make([]float64, len(ext)-1)
+	for i := range dt {
+		dt[i] = math.Pow(ext[i].Distance(ext[i+1]), alpha)
+		if dt[i] == 0 {
+			dt[i] = 1
+		}
+	}
+
+	// tangent at ext[i] relative to the knot parameter
+	tangent := func(i int) d2.V {
+		d0, d1 := dt[i-1], dt[i]
+		p0, p1, p2 := ext[i-1], ext[i], ext[i+1]
+		return p1.Subtract(p0).Multiply(1 / d0).
+			Subtract(p2.Subtract(p0).Multiply(1 / (d0 + d1))).
+			Add(p2.Subtract(p1).Multiply(1 / d1))
+	}
+
+	out := make(Spline, ln-1)
+	for i := range out {
+		j := i + 1
+		p0, p1 := ext[j], ext[j+1]
+		s := dt[j] / 3
+		out[i] = bezier.Bezier{
+			p0,
+			p0.Add(tangent(j).Multiply(s)),
+			p1.Add(tangent(j + 1).Multiply(-s)),
+			p1,
+		}
+	}
+	return out
+}
+
+// Natural creates a natural cubic spline through the points. The second
+// derivative is continuous and is zero at both ends.
+func Natural(pts []d2.Pt) Spline {
+	ln := len(pts)
+	if ln < 2 {
+		return nil
+	}
+	a, b, c, d := tridiagonal(pts)
+	b[0], c[0] = 2, 1
+	d[0] = pts[1].Subtract(pts[0]).Multiply(3)
+	a[ln-1], b[ln-1] = 1, 2
+	d[ln-1] = pts[ln-1].Subtract(pts[ln-2]).Multiply(3)
+	return Hermite(pts, solve(a, b, c, d))
+}
+
+// Clamped creates a cubic spline through the points with the second derivative
+// continuous and the given tangents at the start and end. The tangents are
+// relative to a single segment, as with Hermite.
+func Clamped(pts []d2.Pt, start, end d2.V) Spline {
+	ln := len(pts)
+	if ln < 2 {
+		return nil
+	}
+	a, b, c, d := tridiagonal(pts)
+	b[0], d[0] = 1, start
+	b[ln-1], d[ln-1] = 1, end
+	return Hermite(pts, solve(a, b, c, d))
+}
+
+// tridiagonal sets up the interior rows of the system that gives the tangents
+// of a cubic spline with a continuous second derivative:
+// D[i-1] + 4*D[i] + D[i+1] = 3*(P[i+1] - P[i-1])
+func tridiagonal(pts []d2.Pt) (a, b, c []float64, d []d2.V) {
+	ln := len(pts)
+	a, b, c = make([]float64, ln), make([]float64, ln), make([]float64, ln)
+	d = make([]d2.V, ln)
+	for i := 1; i < ln-1; i++ {
+		a[i], b[i], c[i] = 1, 4, 1
+		d[i] = pts[i+1].Subtract(pts[i-1]).Multiply(3)
+	}
+	return
+}
+
+// solve uses the Thomas algorithm to solve the tridiagonal system where a is
+// the sub-diagonal, b is the diagonal and c is the super-diagonal.
+func solve(a, b, c []float64, d []d2.V) []d2.V {
+	ln := len(d)
+	cp := make([]float64, ln)
+	dp := make([]d2.V, ln)
+	cp[0] = c[0] / b[0]
+	dp[0] = d[0].Multiply(1 / b[0])
+	for i := 1; i < ln; i++ {
+		m := b[i] - a[i]*cp[i-1]
+		cp[i] = c[i] / m
+		dp[i] = d[i].Subtract(dp[i-1].Multiply(a[i])).Multiply(1 / m)
+	}
+	for i := ln - 2; i >= 0; i-- {
+		dp[i] = dp[i].Subtract(dp[i+1].Multiply(cp[i]))
+	}
+	return dp
+}
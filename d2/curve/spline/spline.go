@@ -0,0 +1,145 @@
+// Package spline provides curves that interpolate a sequence of points. Each
+// spline is represented as a chain of cubic Bezier curves.
+package spline
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/bezier"
+)
+
+// Spline is a chain of cubic Bezier curves joined end to end. Each segment is
+// weighted equally, so Pt1(i/n) is the start of segment i.
+type Spline []bezier.Bezier
+
+// Local converts a parametric value relative to the Spline to the index of a
+// segment and a parametric value relative to that segment.
+func (s Spline) Local(t0 float64) (int, float64) {
+	ln := len(s)
+	ts := t0 * float64(ln)
+	idx := int(math.Floor(ts))
+	if idx > ln-1 {
+		idx = ln - 1
+	} else if idx < 0 {
+		idx = 0
+	}
+	return idx, ts - float64(idx)
+}
+
+// Pt1 fulfills d2.Pt1.
+func (s Spline) Pt1(t0 float64) d2.Pt {
+	if len(s) == 0 {
+		return d2.Pt{}
+	}
+	idx, t := s.Local(t0)
+	return s[idx].Pt1(t)
+}
+
+// V1 fulfills d2.V1.
+func (s Spline) V1(t0 float64) d2.V {
+	if len(s) == 0 {
+		return d2.V{}
+	}
+	idx, t := s.Local(t0)
+	return s[idx].V1(t).Multiply(float64(len(s)))
+}
+
+// L fulfills d2.Limiter.
+func (Spline) L(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// VL fulfills d2.VLimiter.
+func (Spline) VL(t, c int) d2.Limit {
+	if t == 1 && c == 1 {
+		return d2.LimitBounded
+	}
+	return d2.LimitUndefined
+}
+
+// Beziers returns the segments of the Spline.
+func (s Spline) Beziers() []bezier.Bezier {
+	return []bezier.Bezier(s)
+}
+
+// Hermite creates a Spline passing through each point with the given tangent.
+// The tangents are relative to a single segment, so a tangent equal to the
+// chord between two points produces a straight line. There must be a tangent
+// for each point, otherwise nil is returned.
+func Hermite(pts []d2.Pt, tangents []d2.V) Spline {
+	if len(pts) < 2 || len(tangents) < len(pts) {
+		return nil
+	}
+	out := make(Spline, len(pts)-1)
+	for i := range out {
+		p0, p1 := pts[i], pts[i+1]
+		out[i] = bezier.Bezier{
+			p0,
+			p0.Add(tangents[i].Multiply(1.0 / 3.0)),
+			p1.Add(tangents[i+1].Multiply(-1.0 / 3.0)),
+			p1,
+		}
+	}
+	return out
+}
+
+// Segment returns the Spline tracing the range [t0, t1]. Each segment is
+// weighted equally so the parameterization is only the same as the original if
+// t0 and t1 fall on the joints between segments.
+func (s Spline) Segment(t0, t1 float64) Spline {
+	if len(s) == 0 {
+		return nil
+	}
+	if t1 < t0 {
+		return s.Segment(t1, t0).Reverse()
+	}
+	i0, lt0 := s.Local(t0)
+	i1, lt1 := s.Local(t1)
+	if lt1 == 0 && i1 > i0 {
+		i1, lt1 = i1-1, 1
+	}
+	if i0 == i1 {
+		return Spline{s[i0].Segment(lt0, lt1)}
+	}
+	out := make(Spline, 0, i1-i0+1)
+	out = append(out, s[i0].Segment(lt0, 1))
+	out = append(out, s[i0+1:i1]...)
+	return append(out, s[i1].Segment(0, lt1))
+}
+
+// Pt1Segment fulfills d2.Segmenter.
+func (s Spline) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return s.Segment(t0, t1)
+}
+
+// Reverse returns the Spline traced in the opposite direction.
+func (s Spline) Reverse() Spline {
+	out := make(Spline, len(s))
+	for i, b := range s {
+		out[len(s)-1-i] = b.Reverse()
+	}
+	return out
+}
+
+// Pt1Reverse fulfills d2.Reverser.
+func (s Spline) Pt1Reverse() d2.Pt1 {
+	return s.Reverse()
+}
+
+// T applies the transform to each segment.
+func (s Spline) T(t *d2.T) Spline {
+	out := make(Spline, len(s))
+	for i, b := range s {
+		out[i] = b.T(t)
+	}
+	return out
+}
+
+// Pt1T fulfills d2.Transformer.
+func (s Spline) Pt1T(t *d2.T) d2.Pt1 {
+	return s.T(t)
+}
@@ -0,0 +1,130 @@
+package spline
+
+import (
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+var pts = []d2.Pt{{0, 0}, {1, 2}, {3, 3}, {4, 1}, {7, 2}}
+
+func assertThroughPts(t *testing.T, s Spline) {
+	assert.Len(t, s, len(pts)-1)
+	fn := float64(len(s))
+	for i, pt := range pts {
+		geomtest.Equal(t, pt, s.Pt1(float64(i)/fn))
+	}
+	geomtest.EqualInDelta(t, d2.AssertV1{}, s, 1e-6)
+}
+
+// secondDerivative at the start and end of segment i relative to the Spline.
+func secondDerivative(s Spline, i int) (d2.V, d2.V) {
+	dd := s[i].Tangent().Bezier.Tangent()
+	return dd.V1(0), dd.V1(1)
+}
+
+func TestHermite(t *testing.T) {
+	vs := []d2.V{{1, 0}, {0, 1}, {1, 1}, {-1, 0}, {0, -1}}
+	s := Hermite(pts, vs)
+	assertThroughPts(t, s)
+	for i, v := range vs {
+		geomtest.Equal(t, v.Multiply(float64(len(s))), s.V1(float64(i)/float64(len(s))))
+	}
+
+	// tangents equal to the chord produce a straight line
+	s = Hermite([]d2.Pt{{0, 0}, {3, 3}}, []d2.V{{3, 3}, {3, 3}})
+	geomtest.Equal(t, d2.Pt{1, 1}, s.Pt1(1.0/3.0))
+
+	assert.Nil(t, Hermite(pts[:1], nil))
+	assert.Nil(t, Hermite(pts, vs[:len(vs)-1]))
+}
+
+func TestCatmullRom(t *testing.T) {
+	s := CatmullRom(pts)
+	assertThroughPts(t, s)
+	// tangents are continuous in direction
+	for i := 1; i < len(s); i++ {
+		v0, v1 := s[i-1].V1(1), s[i].V1(0)
+		geomtest.Equal(t, 0.0, v0.Cross(v1))
+		assert.True(t, v0.Dot(v1) > 0)
+	}
+
+	// collinear points stay on the line
+	s = CatmullRom([]d2.Pt{{0, 0}, {1, 1}, {3, 3}, {7, 7}})
+	for i := 0.0; i <= 1.0; i += 0.05 {
+		pt := s.Pt1(i)
+		geomtest.Equal(t, pt.X, pt.Y)
+	}
+
+	s = CatmullRomAlpha(pts, 0)
+	assertThroughPts(t, s)
+	for i := 1; i < len(s); i++ {
+		geomtest.Equal(t, s[i-1].V1(1), s[i].V1(0))
+	}
+}
+
+func TestNatural(t *testing.T) {
+	s := Natural(pts)
+	assertThroughPts(t, s)
+	for i := 1; i < len(s); i++ {
+		geomtest.Equal(t, s[i-1].V1(1), s[i].V1(0))
+		_, a := secondDerivative(s, i-1)
+		b, _ := secondDerivative(s, i)
+		geomtest.Equal(t, a, b)
+	}
+	a, _ := secondDerivative(s, 0)
+	_, b := secondDerivative(s, len(s)-1)
+	geomtest.Equal(t, d2.V{}, a)
+	geomtest.Equal(t, d2.V{}, b)
+
+	s = Natural(pts[:2])
+	geomtest.Equal(t, pts[0].Add(pts[1].Subtract(pts[0]).Multiply(0.25)), s.Pt1(0.25))
+}
+
+func TestClamped(t *testing.T) {
+	start, end := d2.V{0, 5}, d2.V{5, 0}
+	s := Clamped(pts, start, end)
+	assertThroughPts(t, s)
+	geomtest.Equal(t, start, s[0].V1(0))
+	geomtest.Equal(t, end, s[len(s)-1].V1(1))
+	for i := 1; i < len(s); i++ {
+		geomtest.Equal(t, s[i-1].V1(1), s[i].V1(0))
+		_, a := secondDerivative(s, i-1)
+		b, _ := secondDerivative(s, i)
+		geomtest.Equal(t, a, b)
+	}
+}
+
+func TestBeziers(t *testing.T) {
+	s := Natural(pts)
+	bs := s.Beziers()
+	assert.Len(t, bs, len(pts)-1)
+	for i, b := range bs {
+		assert.Len(t, b, 4)
+		geomtest.Equal(t, b.Pt1(0.5), s.Pt1((float64(i)+0.5)/float64(len(bs))))
+	}
+	assert.Equal(t, d2.LimitBounded, s.L(1, 1))
+	assert.Equal(t, d2.LimitUndefined, s.VL(2, 1))
+}
+
+func TestSegmentReverseT(t *testing.T) {
+	s := CatmullRom(pts)
+	seg := s.Segment(0.1, 0.6)
+	geomtest.Equal(t, s.Pt1(0.1), seg.Pt1(0))
+	geomtest.Equal(t, s.Pt1(0.6), seg.Pt1(1))
+	assert.Len(t, seg, 3)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		// Segment boundaries line up so the parameterization is preserved.
+		geomtest.Equal(t, s.Pt1(0.25+i*0.5), s.Segment(0.25, 0.75).Pt1(i))
+	}
+
+	r := s.Reverse()
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	st := s.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, s.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(s.Pt1(i)), st.Pt1(i))
+	}
+}
@@ -0,0 +1,292 @@
+// Package sample generates points that are uniformly distributed over the area
+// of a shape.
+package sample
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+)
+
+// RejectionLimit is the number of points a Sampler will reject in a row before
+// giving up. It only applies to shapes that are sampled by rejection.
+var RejectionLimit = 1000
+
+// PoissonDiskTries is the number of candidates PoissonDisk tries around each
+// point before moving on.
+var PoissonDiskTries = 30
+
+// Sampler generates points that are uniformly distributed over the area of a
+// shape. All randomness comes from the *rand.Rand it was created with, so a
+// seeded source produces the same points every time.
+type Sampler struct {
+	r *rand.Rand
+	c shape.Container
+	// tris cover the shape, or the convex hull of the shape if reject is true.
+	tris []triangle.Triangle
+	// areas is the cumulative area of tris
+	areas    []float64
+	concave  *polygon.ConcavePolygon
+	reject   bool
+	min, max d2.Pt
+}
+
+// New creates a Sampler for the shape. Polygons, Regions and triangles are
+// triangulated, boxes are sampled directly and a ConcavePolygon is sampled the
+// same as NewConcave. Any other shape is sampled by generating points in its
+// convex hull and rejecting those it does not contain.
+func New(r *rand.Rand, s shape.Shape) *Sampler {
+	out := &Sampler{
+		r: r,
+		c: s,
+	}
+	switch t := s.(type) {
+	case polygon.ConcavePolygon:
+		return NewConcave(r, t)
+	case polygon.Polygon:
+		out.setTriangles(polygon.GetTriangles(t.FindTriangles(), t))
+	case polygon.Region:
+		p := t.Polygon()
+		out.setTriangles(polygon.GetTriangles(p.FindTriangles(), p))
+	case *triangle.Triangle:
+		out.setTriangles([]*triangle.Triangle{t})
+	case *box.Box:
+		out.setTriangles([]*triangle.Triangle{
+			{t[0], {t[1].X, t[0].Y}, t[1]},
+			{t[0], t[1], {t[0].X, t[1].Y}},
+		})
+	default:
+		out.reject = true
+		out.setTriangles(fan(s.ConvexHull()))
+	}
+	return out
+}
+
+// NewConcave creates a Sampler for a ConcavePolygon using
+// ConcavePolygon.AreaPt2.
+func NewConcave(r *rand.Rand, c polygon.ConcavePolygon) *Sampler {
+	out := &Sampler{
+		r:       r,
+		c:       c,
+		concave: &c,
+	}
+	first := true
+	for _, pc := range c.Pieces() {
+		min, max := pc.BoundingBox()
+		if first {
+			out.min, out.max, first = min, max, false
+		} else {
+			out.min, out.max = d2.Min(out.min, min), d2.Max(out.max, max)
+		}
+	}
+	return out
+}
+
+func fan(hull []d2.Pt) []*triangle.Triangle {
+	if len(hull) < 3 {
+		return nil
+	}
+	out := make([]*triangle.Triangle, len(hull)-2)
+	for i := range out {
+		out[i] = &triangle.Triangle{hull[0], hull[i+1], hull[i+2]}
+	}
+	return out
+}
+
+func (s *Sampler) setTriangles(ts []*triangle.Triangle) {
+	s.tris = make([]triangle.Triangle, len(ts))
+	s.areas = make([]float64, len(ts))
+	var sum float64
+	for i, t := range ts {
+		s.tris[i] = *t
+		sum += t.Area()
+		s.areas[i] = sum
+		min, max := t.BoundingBox()
+		if i == 0 {
+			s.min, s.max = min, max
+		} else {
+			s.min, s.max = d2.Min(s.min, min), d2.Max(s.max, max)
+		}
+	}
+}
+
+// BoundingBox fulfills shape.BoundingBoxer. It is the bounding box of the area
+// points are generated in.
+func (s *Sampler) BoundingBox() (min, max d2.Pt) {
+	return s.min, s.max
+}
+
+// Contains returns true if the underlying shape contains pt.
+func (s *Sampler) Contains(pt d2.Pt) bool {
+	return s.c.Contains(pt)
+}
+
+// Pt returns a random point in the shape. It only returns false if the shape
+// is sampled by rejection and RejectionLimit points were rejected.
+func (s *Sampler) Pt() (d2.Pt, bool) {
+	if s.concave != nil {
+		return s.concave.AreaPt2(s.r.Float64(), s.r.Float64()), true
+	}
+	if len(s.tris) == 0 {
+		return d2.Pt{}, false
+	}
+	if !s.reject {
+		return s.triPt(), true
+	}
+	for i := 0; i < RejectionLimit; i++ {
+		if pt := s.triPt(); s.c.Contains(pt) {
+			return pt, true
+		}
+	}
+	return d2.Pt{}, false
+}
+
+// triPt chooses a triangle weighted by area and a uniform point in it.
+func (s *Sampler) triPt() d2.Pt {
+	i := sort.SearchFloat64s(s.areas, s.r.Float64()*s.areas[len(s.areas)-1])
+	if i == len(s.areas) {
+		i--
+	}
+	t := &s.tris[i]
+	u, v := s.r.Float64(), s.r.Float64()
+	if u+v > 1 {
+		// reflect the point back into the triangle
+		u, v = 1-u, 1-v
+	}
+	return t[0].Add(t[1].Subtract(t[0]).Multiply(u)).Add(t[2].Subtract(t[0]).Multiply(v))
+}
+
+// Pts appends n random points to buf. Fewer than n are appended if Pt fails.
+func (s *Sampler) Pts(n int, buf []d2.Pt) []d2.Pt {
+	for i := 0; i < n; i++ {
+		pt, ok := s.Pt()
+		if !ok {
+			break
+		}
+		buf = append(buf, pt)
+	}
+	return buf
+}
+
+// PoissonDisk uses Bridson's algorithm to fill the shape with points that are
+// no closer than minDist to each other. New points are tried around existing
+// points until PoissonDiskTries attempts in a row fail, then a new starting
+// point is taken from Pt so shapes with several parts are filled.
+func (s *Sampler) PoissonDisk(minDist float64) []d2.Pt {
+	if minDist <= 0 {
+		return nil
+	}
+	g := newDiskGrid(s.min, s.max, minDist)
+	var out []d2.Pt
+	var active []int
+	add := func(pt d2.Pt) {
+		g.add(pt, len(out))
+		active = append(active, len(out))
+		out = append(out, pt)
+	}
+
+	for {
+		seeded := false
+		for i := 0; i < PoissonDiskTries; i++ {
+			pt, ok := s.Pt()
+			if !ok {
+				break
+			}
+			if g.inside(pt) && g.fits(pt, out) {
+				add(pt)
+				seeded = true
+				break
+			}
+		}
+		if !seeded {
+			return out
+		}
+
+		for len(active) > 0 {
+			ai := s.r.Intn(len(active))
+			c := out[active[ai]]
+			found := false
+			for i := 0; i < PoissonDiskTries; i++ {
+				// uniform by area in the annulus from minDist to 2*minDist
+				m := minDist * math.Sqrt(1+3*s.r.Float64())
+				sn, cs := math.Sincos(s.r.Float64() * 2 * math.Pi)
+				pt := c.Add(d2.V{cs * m, sn * m})
+				if g.inside(pt) && s.c.Contains(pt) && g.fits(pt, out) {
+					add(pt)
+					found = true
+					break
+				}
+			}
+			if !found {
+				active[ai] = active[len(active)-1]
+				active = active[:len(active)-1]
+			}
+		}
+	}
+}
+
+// diskGrid has cells small enough that each holds at most one point.
+type diskGrid struct {
+	min      d2.Pt
+	cell, r2 float64
+	w, h     int
+	// cells holds the index of the point plus one, so 0 is empty.
+	cells []int
+}
+
+func newDiskGrid(min, max d2.Pt, minDist float64) *diskGrid {
+	cell := minDist / math.Sqrt2
+	v := max.Subtract(min)
+	w, h := int(v.X/cell)+1, int(v.Y/cell)+1
+	return &diskGrid{
+		min:   min,
+		cell:  cell,
+		r2:    minDist * minDist,
+		w:     w,
+		h:     h,
+		cells: make([]int, w*h),
+	}
+}
+
+func (g *diskGrid) idx(pt d2.Pt) (int, int) {
+	v := pt.Subtract(g.min)
+	return int(v.X / g.cell), int(v.Y / g.cell)
+}
+
+func (g *diskGrid) inside(pt d2.Pt) bool {
+	if pt.X < g.min.X || pt.Y < g.min.Y {
+		return false
+	}
+	x, y := g.idx(pt)
+	return x < g.w && y < g.h
+}
+
+func (g *diskGrid) add(pt d2.Pt, i int) {
+	x, y := g.idx(pt)
+	g.cells[y*g.w+x] = i + 1
+}
+
+// fits returns true if no point in pts is within minDist of pt.
+func (g *diskGrid) fits(pt d2.Pt, pts []d2.Pt) bool {
+	x, y := g.idx(pt)
+	for cy := y - 2; cy <= y+2; cy++ {
+		if cy < 0 || cy >= g.h {
+			continue
+		}
+		for cx := x - 2; cx <= x+2; cx++ {
+			if cx < 0 || cx >= g.w {
+				continue
+			}
+			if i := g.cells[cy*g.w+cx]; i > 0 && pts[i-1].Subtract(pt).Mag2() < g.r2 {
+				return false
+			}
+		}
+	}
+	return true
+}
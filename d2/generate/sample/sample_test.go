@@ -0,0 +1,159 @@
+package sample
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/ellipse"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+var lShape = polygon.Polygon{{0, 0}, {3, 0}, {3, 1}, {1, 1}, {1, 3}, {0, 3}}
+
+type sampleCase struct {
+	name     string
+	centroid d2.Pt
+	*Sampler
+}
+
+// samplers creates a Sampler for each test shape. Each has its own source
+// seeded with seed so the points do not depend on the order they are used in.
+func samplers(seed int64) []sampleCase {
+	r := func() *rand.Rand { return rand.New(rand.NewSource(seed)) }
+	return []sampleCase{
+		{"polygon", lShape.Centroid(), New(r(), lShape)},
+		{"reversed", lShape.Centroid(), New(r(), lShape.Reverse())},
+		{"concave", lShape.Centroid(), NewConcave(r(), polygon.NewConcavePolygon(lShape))},
+		{"region", d2.Pt{1.5, 1.5}, New(r(), polygon.NewRegion(
+			polygon.Polygon{{0, 0}, {3, 0}, {3, 3}, {0, 3}},
+			polygon.Polygon{{1, 1}, {2, 1}, {2, 2}, {1, 2}},
+		))},
+		{"triangle", d2.Pt{1, 1}, New(r(), &triangle.Triangle{{0, 0}, {3, 0}, {0, 3}})},
+		{"box", d2.Pt{1.5, 1}, New(r(), box.New(d2.Pt{0, 0}, d2.Pt{3, 2}))},
+		{"ellipse", d2.Pt{1.5, 1.5}, New(r(), ellipse.New(d2.Pt{0, 1}, d2.Pt{3, 2}, 4))},
+	}
+}
+
+func sampler(cs []sampleCase, name string) *Sampler {
+	for _, c := range cs {
+		if c.name == name {
+			return c.Sampler
+		}
+	}
+	return nil
+}
+
+func TestSamplerUniform(t *testing.T) {
+	for _, c := range samplers(1) {
+		s := c.Sampler
+		t.Run(c.name, func(t *testing.T) {
+			pts := s.Pts(20000, nil)
+			assert.Len(t, pts, 20000)
+			var sum d2.V
+			for _, pt := range pts {
+				assert.True(t, s.Contains(pt))
+				sum = sum.Add(pt.V())
+			}
+			geomtest.EqualInDelta(t, c.centroid, sum.Multiply(1.0/float64(len(pts))).Pt(), 0.03)
+		})
+	}
+
+	// the arms of the L shape have an area of 2 and the corner has an area of 1
+	pts := sampler(samplers(2), "concave").Pts(30000, nil)
+	var counts [3]int
+	for _, pt := range pts {
+		switch {
+		case pt.X > 1:
+			counts[0]++
+		case pt.Y > 1:
+			counts[1]++
+		default:
+			counts[2]++
+		}
+	}
+	assert.InDelta(t, 12000, counts[0], 400)
+	assert.InDelta(t, 12000, counts[1], 400)
+	assert.InDelta(t, 6000, counts[2], 400)
+}
+
+func TestSamplerSeed(t *testing.T) {
+	a, b := samplers(3), samplers(3)
+	for i := range a {
+		assert.Equal(t, a[i].Pts(10, nil), b[i].Pts(10, nil), a[i].name)
+	}
+	assert.Equal(t, sampler(a, "ellipse").PoissonDisk(0.2), sampler(b, "ellipse").PoissonDisk(0.2))
+	assert.NotEqual(t, sampler(a, "box").Pts(10, nil), sampler(samplers(4), "box").Pts(10, nil))
+
+	// New samples a ConcavePolygon the same as NewConcave
+	c := polygon.NewConcavePolygon(lShape)
+	r1, r2 := rand.New(rand.NewSource(8)), rand.New(rand.NewSource(8))
+	assert.Equal(t, NewConcave(r1, c).Pts(10, nil), New(r2, c).Pts(10, nil))
+}
+
+// empty is a shape.Shape that contains no points.
+type empty struct{ *box.Box }
+
+func (empty) Contains(d2.Pt) bool { return false }
+
+func TestSamplerRejectionLimit(t *testing.T) {
+	var s shape.Shape = empty{box.New(d2.Pt{0, 0}, d2.Pt{1, 1})}
+	smp := New(rand.New(rand.NewSource(5)), s)
+	_, ok := smp.Pt()
+	assert.False(t, ok)
+	assert.Len(t, smp.Pts(10, nil), 0)
+	assert.Len(t, smp.PoissonDisk(0.1), 0)
+}
+
+func TestPoissonDisk(t *testing.T) {
+	const minDist = 0.1
+	for _, c := range samplers(6) {
+		s := c.Sampler
+		t.Run(c.name, func(t *testing.T) {
+			pts := s.PoissonDisk(minDist)
+			for i, a := range pts {
+				assert.True(t, s.Contains(a))
+				for _, b := range pts[i+1:] {
+					assert.True(t, a.Distance(b) >= minDist)
+				}
+			}
+
+			// every point in the shape should be near one of the points
+			var far int
+			for _, pt := range s.Pts(1000, nil) {
+				closest := pts[0].Distance(pt)
+				for _, p := range pts[1:] {
+					if d := p.Distance(pt); d < closest {
+						closest = d
+					}
+				}
+				if closest >= 2*minDist {
+					far++
+				}
+			}
+			assert.True(t, far < 5, far)
+		})
+	}
+
+	// both halves of a shape with two parts are filled
+	r := rand.New(rand.NewSource(7))
+	two := New(r, shape.Union{
+		box.New(d2.Pt{0, 0}, d2.Pt{1, 1}),
+		box.New(d2.Pt{5, 0}, d2.Pt{6, 1}),
+	})
+	var left, right int
+	for _, pt := range two.PoissonDisk(minDist) {
+		if pt.X < 3 {
+			left++
+		} else {
+			right++
+		}
+	}
+	assert.True(t, left > 40)
+	assert.True(t, right > 40)
+}
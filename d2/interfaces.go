@@ -49,6 +49,11 @@ type V1c0 interface {
 	V1c0() V1
 }
 
+// V2 takes one parametric value and returns the second derivative as a V
+type V2 interface {
+	V2(t0 float64) V
+}
+
 // Pt1V1 has one argument parametric methods for both Pt1 and V1, typically this
 // represents a curve and it's derivative.
 type Pt1V1 interface {
@@ -56,6 +61,23 @@ type Pt1V1 interface {
 	V1
 }
 
+// Segmenter returns a curve that matches the range [t0, t1] of the original
+// curve over the range [0,1].
+type Segmenter interface {
+	Pt1Segment(t0, t1 float64) Pt1
+}
+
+// Reverser returns a curve that traces the original curve in the opposite
+// direction.
+type Reverser interface {
+	Pt1Reverse() Pt1
+}
+
+// Transformer returns a curve with the transform applied.
+type Transformer interface {
+	Pt1T(t *T) Pt1
+}
+
 // Limit is used to indicate if a parametric method is bounded to [0,1] or
 // unbounded
 type Limit byte
@@ -118,6 +140,37 @@ func GetV1(of Pt1) V1 {
 	return V1Wrapper{of, 0}
 }
 
+// V2Wrapper takes any V1 and approximates V2
+type V2Wrapper struct {
+	V     V1
+	Small float64
+}
+
+// V2 approximates V2 from two derivatives close together on either side of t0
+func (v2 V2Wrapper) V2(t0 float64) V {
+	if v2.Small == 0 {
+		v2.Small = small
+	}
+	big := 1 / (2 * v2.Small)
+	return v2.V.V1(t0 + v2.Small).Subtract(v2.V.V1(t0 - v2.Small)).Multiply(big)
+}
+
+// smallV2 is used when both derivatives have to be approximated, using small
+// for both would lose too much precision.
+const smallV2 = 1e-5
+
+// GetV2 takes any Pt1 and returns the optimal V2.
+func GetV2(of Pt1) V2 {
+	if v2, ok := of.(V2); ok {
+		return v2
+	}
+	v1 := GetV1(of)
+	if _, ok := v1.(V1Wrapper); ok {
+		return V2Wrapper{V1Wrapper{of, smallV2}, smallV2}
+	}
+	return V2Wrapper{v1, 0}
+}
+
 // Pt2c1Wrapper wraps any Pt2 to convert it to a Pt2c1
 type Pt2c1Wrapper struct {
 	Pt2
@@ -202,3 +255,36 @@ func (AssertV1) AssertEqual(actual interface{}, t cmpr.Tolerance) error {
 	}
 	return badPoints
 }
+
+// AssertV2 checks that the second derivative is close the approximation from
+// the first derivative.
+type AssertV2 struct{}
+
+// AssertEqual fulfils geomtest.AssertEqualizer.
+func (AssertV2) AssertEqual(actual interface{}, t cmpr.Tolerance) error {
+	// V2Wrapper is not very accurate, so we reduce the required tolerance.
+	t = cmpr.Tolerance(math.Sqrt(float64(t)))
+
+	v2, ok := actual.(V2)
+	if !ok {
+		return geomerr.TypeMismatch(V2(nil), actual)
+	}
+	v1, ok := actual.(V1)
+	if !ok {
+		return geomerr.TypeMismatch(V1(nil), actual)
+	}
+	approx := V2Wrapper{v1, 0}
+
+	var badPoints geomerr.SliceErrs
+	for i := 0.0; i <= 1.0; i += 0.01 {
+		expected, got := approx.V2(i), v2.V2(i)
+		if expected.AssertEqual(got, t) != nil {
+			badPoints = badPoints.AppendF(int(i/0.01), "Bad second derivative at V2(%0.2f) Expected: %s Got:%s", i, expected, got)
+		}
+	}
+
+	if len(badPoints) == 0 {
+		return nil
+	}
+	return badPoints
+}
@@ -0,0 +1,290 @@
+// Package bounding finds the smallest shapes of a given kind that contain a
+// shape. Everything works from the convex hull, most of it using rotating
+// calipers.
+package bounding
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/adamcolton/geom/angle"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/ellipse"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+)
+
+// Rect is a rectangle that may be rotated. The vertices proceed
+// counter-clockwise.
+type Rect [4]d2.Pt
+
+// Polygon returns the vertices of the rectangle as a Polygon.
+func (r Rect) Polygon() polygon.Polygon {
+	return polygon.Polygon{r[0], r[1], r[2], r[3]}
+}
+
+// Size returns the length of the side from r[0] to r[1] as w and the length of
+// the side from r[1] to r[2] as h.
+func (r Rect) Size() (w, h float64) {
+	return r[0].Distance(r[1]), r[1].Distance(r[2])
+}
+
+// Angle of the side from r[0] to r[1].
+func (r Rect) Angle() angle.Rad {
+	return r[1].Subtract(r[0]).Angle()
+}
+
+// Area of the rectangle. Fulfills shape.Area.
+func (r Rect) Area() float64 {
+	w, h := r.Size()
+	return w * h
+}
+
+// SignedArea of the rectangle. Fulfills shape.Area.
+func (r Rect) SignedArea() float64 {
+	return r.Area()
+}
+
+// Perimeter of the rectangle. Fulfills shape.Perimeter.
+func (r Rect) Perimeter() float64 {
+	w, h := r.Size()
+	return 2 * (w + h)
+}
+
+// Centroid returns the center of the rectangle. Fulfills shape.Centroid.
+func (r Rect) Centroid() d2.Pt {
+	return d2.Pt{(r[0].X + r[2].X) / 2, (r[0].Y + r[2].Y) / 2}
+}
+
+// ConvexHull returns the vertices of the rectangle. Fulfills
+// shape.ConvexHuller.
+func (r Rect) ConvexHull() []d2.Pt {
+	return r[:]
+}
+
+// hull returns the convex hull of c proceeding counter-clockwise without
+// repeated points.
+func hull(c shape.ConvexHuller) []d2.Pt {
+	pts := c.ConvexHull()
+	var out []d2.Pt
+	for i, pt := range pts {
+		if pt != pts[(i+1)%len(pts)] {
+			out = append(out, pt)
+		}
+	}
+	if len(out) == 0 && len(pts) > 0 {
+		out = pts[:1]
+	}
+	if polygon.Polygon(out).SignedArea() < 0 {
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+	return out
+}
+
+// MinAreaRect returns the rectangle with the smallest area that contains c.
+// One side of the rectangle lies along a side of the convex hull. It takes
+// O(n) time for a convex hull with n points.
+func MinAreaRect(c shape.ConvexHuller) Rect {
+	return minRect(hull(c), func(w, h float64) float64 { return w * h })
+}
+
+// MinPerimeterRect returns the rectangle with the smallest perimeter that
+// contains c. One side of the rectangle lies along a side of the convex hull. It
+// takes O(n) time for a convex hull with n points.
+func MinPerimeterRect(c shape.ConvexHuller) Rect {
+	return minRect(hull(c), func(w, h float64) float64 { return w + h })
+}
+
+// minRect uses rotating calipers to check the rectangle on each side of the
+// hull. The points furthest along the side in both directions and furthest
+// from the side only ever move forward around the hull.
+func minRect(h []d2.Pt, cost func(w, h float64) float64) Rect {
+	n := len(h)
+	switch n {
+	case 0:
+		return Rect{}
+	case 1:
+		return Rect{h[0], h[0], h[0], h[0]}
+	}
+
+	var out Rect
+	best := math.Inf(1)
+	right, top, left := 0, 0, 0
+	for i := 0; i < n; i++ {
+		a := h[i]
+		u := h[(i+1)%n].Subtract(a)
+		u = u.Multiply(1 / u.Mag())
+		v := d2.V{-u.Y, u.X}
+		along := func(j int, d d2.V) float64 {
+			return d.Dot(h[j%n].Subtract(a))
+		}
+
+		if right < i+1 {
+			right = i + 1
+		}
+		for along(right+1, u) > along(right, u) {
+			right++
+		}
+		if top < right {
+			top = right
+		}
+		for along(top+1, v) > along(top, v) {
+			top++
+		}
+		if left < top {
+			left = top
+		}
+		for along(left+1, u) < along(left, u) {
+			left++
+		}
+
+		min, max, height := along(left, u), along(right, u), along(top, v)
+		if c := cost(max-min, height); c < best {
+			best = c
+			out = Rect{
+				a.Add(u.Multiply(min)),
+				a.Add(u.Multiply(max)),
+				a.Add(u.Multiply(max)).Add(v.Multiply(height)),
+				a.Add(u.Multiply(min)).Add(v.Multiply(height)),
+			}
+		}
+	}
+	return out
+}
+
+// Diameter returns the two points of c that are furthest apart. It takes O(n)
+// time for a convex hull with n points.
+func Diameter(c shape.ConvexHuller) (d2.Pt, d2.Pt) {
+	h := hull(c)
+	n := len(h)
+	switch n {
+	case 0:
+		return d2.Pt{}, d2.Pt{}
+	case 1:
+		return h[0], h[0]
+	}
+
+	var a, b d2.Pt
+	best := -1.0
+	check := func(p, q d2.Pt) {
+		if d := p.Subtract(q).Mag2(); d > best {
+			best, a, b = d, p, q
+		}
+	}
+	// each antipodal pair is found as the point furthest from a side
+	far := 1
+	for i := 0; i < n; i++ {
+		p, q := h[i], h[(i+1)%n]
+		s := q.Subtract(p)
+		dist := func(j int) float64 {
+			return s.Cross(h[j%n].Subtract(p))
+		}
+		if far < i+1 {
+			far = i + 1
+		}
+		for dist(far+1) > dist(far) {
+			far++
+		}
+		check(p, h[far%n])
+		check(q, h[far%n])
+	}
+	return a, b
+}
+
+// Width returns the smallest distance between two parallel lines that contain c
+// between them. It takes O(n) time for a convex hull with n points.
+func Width(c shape.ConvexHuller) float64 {
+	h := hull(c)
+	n := len(h)
+	if n < 3 {
+		return 0
+	}
+	best := math.Inf(1)
+	far := 1
+	for i := 0; i < n; i++ {
+		p := h[i]
+		u := h[(i+1)%n].Subtract(p)
+		u = u.Multiply(1 / u.Mag())
+		dist := func(j int) float64 {
+			return u.Cross(h[j%n].Subtract(p))
+		}
+		if far < i+1 {
+			far = i + 1
+		}
+		for dist(far+1) > dist(far) {
+			far++
+		}
+		best = math.Min(best, dist(far))
+	}
+	return best
+}
+
+// Circle returns the smallest circle that contains c using Welzl's algorithm.
+// The points of the convex hull are shuffled with a fixed seed so the expected
+// time is O(n) and the result is repeatable.
+func Circle(c shape.ConvexHuller) ellipse.Circle {
+	h := append([]d2.Pt(nil), hull(c)...)
+	if len(h) == 0 {
+		return ellipse.NewCircle(d2.Pt{}, 0)
+	}
+	r := rand.New(rand.NewSource(1))
+	r.Shuffle(len(h), func(i, j int) {
+		h[i], h[j] = h[j], h[i]
+	})
+
+	// this is the iterative form of Welzl's algorithm; each loop adds a
+	// point that must be on the boundary.
+	e := enclosing{h[0], 0}
+	for i := 1; i < len(h); i++ {
+		if e.contains(h[i]) {
+			continue
+		}
+		e = enclosing{h[i], 0}
+		for j := 0; j < i; j++ {
+			if e.contains(h[j]) {
+				continue
+			}
+			e = diametric(h[i], h[j])
+			for k := 0; k < j; k++ {
+				if !e.contains(h[k]) {
+					e = circumscribed(h[i], h[j], h[k])
+				}
+			}
+		}
+	}
+	return ellipse.NewCircle(e.c, e.r)
+}
+
+type enclosing struct {
+	c d2.Pt
+	r float64
+}
+
+func (e enclosing) contains(pt d2.Pt) bool {
+	return e.c.Distance(pt) <= e.r*(1+1e-12)+1e-12
+}
+
+func diametric(a, b d2.Pt) enclosing {
+	c := d2.Pt{(a.X + b.X) / 2, (a.Y + b.Y) / 2}
+	return enclosing{c, c.Distance(a)}
+}
+
+// circumscribed returns the circle with all three points on its boundary. If
+// the points are collinear the circle on the two furthest apart is used.
+func circumscribed(a, b, c d2.Pt) enclosing {
+	t := &triangle.Triangle{a, b, c}
+	if t.Area() > 1e-12*t.Perimeter()*t.Perimeter() {
+		ctr := t.CircumCenter()
+		return enclosing{ctr, ctr.Distance(a)}
+	}
+	e := diametric(a, b)
+	for _, f := range [2]enclosing{diametric(a, c), diametric(b, c)} {
+		if f.r > e.r {
+			e = f
+		}
+	}
+	return e
+}
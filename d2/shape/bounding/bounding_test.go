@@ -0,0 +1,111 @@
+package bounding
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/angle"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func rotatedRect(w, h float64, a angle.Rad) polygon.Polygon {
+	s, c := math.Sincos(float64(a))
+	out := polygon.Polygon{{0, 0}, {w, 0}, {w, h}, {0, h}}
+	for i, pt := range out {
+		out[i] = d2.Pt{pt.X*c - pt.Y*s + 3, pt.X*s + pt.Y*c - 1}
+	}
+	return out
+}
+
+func TestRotatedRect(t *testing.T) {
+	p := rotatedRect(4, 1, angle.Deg(30))
+
+	r := MinAreaRect(p)
+	assert.InDelta(t, 4.0, r.Area(), 1e-9)
+	assert.InDelta(t, 10.0, r.Perimeter(), 1e-9)
+	for _, pt := range p {
+		geomtest.Equal(t, pt, r.Polygon().Closest(pt))
+	}
+	geomtest.Equal(t, p.Centroid(), r.Centroid())
+
+	r = MinPerimeterRect(p)
+	assert.InDelta(t, 10.0, r.Perimeter(), 1e-9)
+
+	a, b := Diameter(p)
+	assert.InDelta(t, math.Sqrt(17), a.Distance(b), 1e-9)
+	assert.InDelta(t, 1.0, Width(p), 1e-9)
+
+	c := Circle(p)
+	assert.InDelta(t, math.Sqrt(17)/2, c.Radius(), 1e-9)
+	geomtest.Equal(t, p.Centroid(), c.Centroid())
+}
+
+func TestCircle(t *testing.T) {
+	// obtuse triangle, the longest side is the diameter
+	c := Circle(polygon.Polygon{{0, 0}, {4, 0}, {2, 0.5}})
+	assert.InDelta(t, 2.0, c.Radius(), 1e-9)
+	geomtest.Equal(t, d2.Pt{2, 0}, c.Centroid())
+
+	// equilateral triangle, all three points are on the circle
+	c = Circle(polygon.RegularPolygonRadius(d2.Pt{1, 1}, 3, 0, 3))
+	assert.InDelta(t, 3.0, c.Radius(), 1e-9)
+	geomtest.Equal(t, d2.Pt{1, 1}, c.Centroid())
+
+	c = Circle(polygon.Polygon{{1, 2}})
+	assert.Equal(t, 0.0, c.Radius())
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(8675309))
+	for i := 0; i < 20; i++ {
+		pts := make(polygon.Polygon, 5+r.Intn(50))
+		for j := range pts {
+			pts[j] = d2.Pt{r.Float64()*10 - 5, r.Float64()*4 - 2}
+		}
+		h := polygon.Polygon(pts.ConvexHull())
+
+		ar, pr := MinAreaRect(pts), MinPerimeterRect(pts)
+		c := Circle(pts)
+		for _, pt := range pts {
+			assert.True(t, ar.Polygon().Contains(pt) || ar.Polygon().Closest(pt).Distance(pt) < 1e-9)
+			assert.True(t, pr.Polygon().Contains(pt) || pr.Polygon().Closest(pt).Distance(pt) < 1e-9)
+			assert.True(t, c.Centroid().Distance(pt) <= c.Radius()+1e-9)
+		}
+		assert.True(t, ar.Area() <= box.New(pts...).Area()+1e-9)
+
+		// check every side and every pair of points
+		minArea, minPerimeter, width := math.Inf(1), math.Inf(1), math.Inf(1)
+		for j, pt := range h {
+			u := h[(j+1)%len(h)].Subtract(pt)
+			u = u.Multiply(1 / u.Mag())
+			v := d2.V{-u.Y, u.X}
+			minU, maxU, maxV := math.Inf(1), math.Inf(-1), 0.0
+			for _, q := range h {
+				d := q.Subtract(pt)
+				minU, maxU = math.Min(minU, u.Dot(d)), math.Max(maxU, u.Dot(d))
+				maxV = math.Max(maxV, v.Dot(d))
+			}
+			minArea = math.Min(minArea, (maxU-minU)*maxV)
+			minPerimeter = math.Min(minPerimeter, 2*(maxU-minU+maxV))
+			width = math.Min(width, maxV)
+		}
+		assert.InDelta(t, minArea, ar.Area(), 1e-9)
+		assert.InDelta(t, minPerimeter, pr.Perimeter(), 1e-9)
+		assert.InDelta(t, width, Width(pts), 1e-9)
+
+		var diameter float64
+		for _, p := range pts {
+			for _, q := range pts {
+				diameter = math.Max(diameter, p.Distance(q))
+			}
+		}
+		a, b := Diameter(pts)
+		assert.InDelta(t, diameter, a.Distance(b), 1e-9)
+		assert.True(t, c.Radius() <= diameter/math.Sqrt(3)+1e-9)
+	}
+}
@@ -0,0 +1,345 @@
+// Package collision detects overlap between convex shapes and finds how far
+// they overlap or how far apart they are. GJK and EPA work with any convex
+// shape through a support function and SAT is used for polygons.
+package collision
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+)
+
+// Result of checking two shapes, A and B, for a collision.
+type Result struct {
+	// Overlap is true if the shapes overlap or touch.
+	Overlap bool
+	// MTV is the minimum translation vector. Moving B by MTV, or A by -MTV,
+	// leaves the shapes touching. It is zero if the shapes do not overlap.
+	MTV d2.V
+	// A and B are contact points on each shape. If the shapes overlap, they
+	// are the deepest points of the overlap and A-B is MTV. Otherwise they are
+	// the closest points on each shape.
+	A, B d2.Pt
+	// Distance between the shapes. It is zero if the shapes overlap.
+	Distance float64
+}
+
+// Depth of the overlap, the magnitude of MTV.
+func (r Result) Depth() float64 {
+	return r.MTV.Mag()
+}
+
+// Supporter is a convex shape defined by its support function. Support
+// returns the point of the shape that is furthest in the direction d.
+type Supporter interface {
+	Support(d d2.V) d2.Pt
+}
+
+// Hull is a Supporter for the convex polygon formed by the points.
+type Hull []d2.Pt
+
+// NewHull creates a Hull from the convex hull of c.
+func NewHull(c shape.ConvexHuller) Hull {
+	return Hull(c.ConvexHull())
+}
+
+// Support fulfills Supporter.
+func (h Hull) Support(d d2.V) d2.Pt {
+	best, out := math.Inf(-1), d2.Pt{}
+	for _, pt := range h {
+		if dot := d.X*pt.X + d.Y*pt.Y; dot > best {
+			best, out = dot, pt
+		}
+	}
+	return out
+}
+
+// Circle is a Supporter for a circle. Unlike the convex hull of an
+// ellipse.Circle, it is exact.
+type Circle struct {
+	Center d2.Pt
+	Radius float64
+}
+
+// Support fulfills Supporter.
+func (c Circle) Support(d d2.V) d2.Pt {
+	m := d.Mag()
+	if m == 0 {
+		return c.Center
+	}
+	return c.Center.Add(d.Multiply(c.Radius / m))
+}
+
+// Collide checks two shapes for a collision. If both are a polygon.Polygon,
+// *box.Box or *triangle.Triangle, SAT is used to find an overlap. Otherwise, or
+// if SAT finds they do not overlap, GJK is used on their convex hulls.
+func Collide(a, b shape.ConvexHuller) Result {
+	pa, aok := satPts(a)
+	pb, bok := satPts(b)
+	if aok && bok {
+		if r := SAT(pa, pb); r.Overlap {
+			return r
+		}
+		return GJK(Hull(pa), Hull(pb))
+	}
+	return GJK(NewHull(a), NewHull(b))
+}
+
+func satPts(c shape.ConvexHuller) ([]d2.Pt, bool) {
+	switch s := c.(type) {
+	case polygon.Polygon:
+		if s.Convex() {
+			return s, true
+		}
+		return s.ConvexHull(), true
+	case *box.Box:
+		return s.ConvexHull(), true
+	case *triangle.Triangle:
+		return s[:], true
+	}
+	return nil, false
+}
+
+// SAT uses the separating axis theorem to check the convex polygons a and b
+// for a collision. The polygons may proceed in either direction. If they do not
+// overlap, only Overlap is set; use GJK to find the distance between them.
+func SAT(a, b []d2.Pt) Result {
+	if len(a) == 0 || len(b) == 0 {
+		return Result{}
+	}
+	best := math.Inf(1)
+	var out Result
+	for i, pts := range [2][]d2.Pt{a, b} {
+		for j, pt := range pts {
+			e := pts[(j+1)%len(pts)].Subtract(pt)
+			m := e.Mag()
+			if m == 0 {
+				continue
+			}
+			n := d2.V{-e.Y / m, e.X / m}
+			minA, maxA := project(a, n)
+			minB, maxB := project(b, n)
+			d := maxA - minB
+			if d1 := maxB - minA; d1 < d {
+				d, n = d1, n.Multiply(-1)
+			}
+			if d < 0 {
+				return Result{}
+			}
+			if d < best {
+				best = d
+				out.MTV = n.Multiply(d)
+				// the contact is the deepest point of the shape that does not
+				// own the axis.
+				if i == 0 {
+					out.B = Hull(b).Support(n.Multiply(-1))
+					out.A = out.B.Add(out.MTV)
+				} else {
+					out.A = Hull(a).Support(n)
+					out.B = out.A.Add(out.MTV.Multiply(-1))
+				}
+			}
+		}
+	}
+	out.Overlap = true
+	return out
+}
+
+func project(pts []d2.Pt, n d2.V) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, pt := range pts {
+		d := n.X*pt.X + n.Y*pt.Y
+		min, max = math.Min(min, d), math.Max(max, d)
+	}
+	return
+}
+
+// Iterations limits the number of steps taken by GJK and EPA. Both converge in
+// a few steps for polygons, curved shapes like Circle may take more.
+var Iterations = 64
+
+// vertex of the Minkowski difference A-B along with the points of A and B that
+// produced it.
+type vertex struct {
+	w, a, b d2.Pt
+}
+
+func support(a, b Supporter, d d2.V) vertex {
+	pa, pb := a.Support(d), b.Support(d.Multiply(-1))
+	return vertex{
+		w: d2.Pt(pa.Subtract(pb)),
+		a: pa,
+		b: pb,
+	}
+}
+
+// GJK checks two convex shapes for a collision using the
+// Gilbert-Johnson-Keerthi distance algorithm. If the shapes overlap, the
+// expanding polytope algorithm (EPA) is used to find the MTV.
+func GJK(a, b Supporter) Result {
+	v := support(a, b, d2.V{1, 0})
+	s := []vertex{v}
+	scale := 1 + d2.V(v.w).Mag()
+	for i := 0; i < Iterations; i++ {
+		c, ok := closest(s)
+		if !ok {
+			return epa(a, b, s, scale)
+		}
+		s = c.simplex
+		p := c.pt.w
+		dist2 := d2.V(p).Mag2()
+		if dist2 < 1e-24*scale*scale {
+			return epa(a, b, s, scale)
+		}
+		w := support(a, b, d2.V(p).Multiply(-1))
+		// stop when the new point gets no closer to the origin
+		if dist2-d2.V(p).Dot(d2.V(w.w)) <= 1e-12*dist2 {
+			break
+		}
+		s = append(s, w)
+	}
+	c, ok := closest(s)
+	if !ok {
+		return epa(a, b, s, scale)
+	}
+	return Result{
+		A:        c.pt.a,
+		B:        c.pt.b,
+		Distance: d2.V(c.pt.w).Mag(),
+	}
+}
+
+type closestResult struct {
+	// pt is the closest point with a and b interpolated the same way
+	pt      vertex
+	simplex []vertex
+}
+
+func lerp(v0, v1 vertex, t float64) vertex {
+	return vertex{
+		w: lerpPt(v0.w, v1.w, t),
+		a: lerpPt(v0.a, v1.a, t),
+		b: lerpPt(v0.b, v1.b, t),
+	}
+}
+
+func lerpPt(p0, p1 d2.Pt, t float64) d2.Pt {
+	return p0.Add(p1.Subtract(p0).Multiply(t))
+}
+
+// segment returns the closest point to the origin on the segment from v0 to v1
+// and the smallest part of the segment that contains it.
+func segment(v0, v1 vertex) closestResult {
+	e := v1.w.Subtract(v0.w)
+	m2 := e.Mag2()
+	if m2 == 0 {
+		return closestResult{v0, []vertex{v0}}
+	}
+	t := -d2.V(v0.w).Dot(e) / m2
+	if t <= 0 {
+		return closestResult{v0, []vertex{v0}}
+	}
+	if t >= 1 {
+		return closestResult{v1, []vertex{v1}}
+	}
+	return closestResult{lerp(v0, v1, t), []vertex{v0, v1}}
+}
+
+// closest finds the point in the simplex closest to the origin and reduces the
+// simplex to the part containing it. It returns false if the origin is inside
+// the simplex.
+func closest(s []vertex) (closestResult, bool) {
+	switch len(s) {
+	case 1:
+		return closestResult{s[0], s}, true
+	case 2:
+		return segment(s[0], s[1]), true
+	}
+	t := triangle.Triangle{s[0].w, s[1].w, s[2].w}
+	// a flat triangle contains every point on its line
+	if e := t[1].Subtract(t[0]); math.Abs(t.SignedArea()) > 1e-12*e.Mag2() && t.Contains(d2.Pt{}) {
+		return closestResult{}, false
+	}
+	var out closestResult
+	best := math.Inf(1)
+	for i := range s {
+		c := segment(s[i], s[(i+1)%3])
+		if d := d2.V(c.pt.w).Mag2(); d < best {
+			best, out = d, c
+		}
+	}
+	return out, true
+}
+
+// epa expands the simplex s, which contains the origin, until it finds the
+// side of the Minkowski difference closest to the origin.
+func epa(a, b Supporter, s []vertex, scale float64) Result {
+	s = fill(a, b, s)
+	if len(s) < 3 {
+		// the Minkowski difference is flat, the shapes only touch
+		return Result{Overlap: true, A: s[0].a, B: s[0].b}
+	}
+	if polygon.Polygon([]d2.Pt{s[0].w, s[1].w, s[2].w}).SignedArea() < 0 {
+		s[1], s[2] = s[2], s[1]
+	}
+
+	var idx int
+	var n d2.V
+	var dist float64
+	for iter := 0; iter < Iterations; iter++ {
+		idx, n, dist = -1, d2.V{}, math.Inf(1)
+		for i, v := range s {
+			e := s[(i+1)%len(s)].w.Subtract(v.w)
+			m := e.Mag()
+			if m == 0 {
+				continue
+			}
+			en := d2.V{e.Y / m, -e.X / m}
+			if d := en.Dot(d2.V(v.w)); d < dist {
+				idx, n, dist = i, en, d
+			}
+		}
+		w := support(a, b, n)
+		if n.Dot(d2.V(w.w))-dist <= 1e-10*scale {
+			break
+		}
+		s = append(s[:idx+1], append([]vertex{w}, s[idx+1:]...)...)
+	}
+
+	c := segment(s[idx], s[(idx+1)%len(s)])
+	return Result{
+		Overlap: true,
+		MTV:     d2.V(c.pt.w),
+		A:       c.pt.a,
+		B:       c.pt.b,
+	}
+}
+
+// fill grows a simplex that contains the origin to a triangle. The origin may
+// be on a vertex or a side of the simplex.
+func fill(a, b Supporter, s []vertex) []vertex {
+	if len(s) == 1 {
+		for _, d := range []d2.V{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			if w := support(a, b, d); w.w != s[0].w {
+				s = append(s, w)
+				break
+			}
+		}
+	}
+	if len(s) == 2 {
+		e := s[1].w.Subtract(s[0].w)
+		n := d2.V{-e.Y, e.X}
+		for _, d := range []d2.V{n, n.Multiply(-1)} {
+			w := support(a, b, d)
+			if math.Abs(e.Cross(w.w.Subtract(s[0].w))) > 1e-12*e.Mag2() {
+				s = append(s, w)
+				break
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,123 @@
+package collision
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxes(t *testing.T) {
+	a := box.New(d2.Pt{0, 0}, d2.Pt{2, 2})
+	b := box.New(d2.Pt{1.5, 1}, d2.Pt{3.5, 3})
+
+	for _, r := range []Result{Collide(a, b), GJK(NewHull(a), NewHull(b))} {
+		assert.True(t, r.Overlap)
+		geomtest.Equal(t, d2.V{0.5, 0}, r.MTV)
+		assert.InDelta(t, 0.5, r.Depth(), 1e-9)
+		geomtest.Equal(t, r.MTV, r.A.Subtract(r.B))
+		assert.InDelta(t, 2.0, r.A.X, 1e-9)
+		assert.Equal(t, 0.0, r.Distance)
+	}
+
+	b = box.New(d2.Pt{5, 6}, d2.Pt{7, 7})
+	r := Collide(a, b)
+	assert.False(t, r.Overlap)
+	assert.InDelta(t, 5.0, r.Distance, 1e-9)
+	geomtest.Equal(t, d2.Pt{2, 2}, r.A)
+	geomtest.Equal(t, d2.Pt{5, 6}, r.B)
+}
+
+func TestCircles(t *testing.T) {
+	a := Circle{d2.Pt{0, 0}, 1}
+	b := Circle{d2.Pt{3, 4}, 3.5}
+	r := GJK(a, b)
+	assert.False(t, r.Overlap)
+	assert.InDelta(t, 0.5, r.Distance, 1e-6)
+	geomtest.EqualInDelta(t, d2.Pt{0.6, 0.8}, r.A, 1e-5)
+
+	b.Radius = 5
+	r = GJK(a, b)
+	assert.True(t, r.Overlap)
+	assert.InDelta(t, 1, r.Depth(), 1e-6)
+	geomtest.EqualInDelta(t, d2.V{0.6, 0.8}, r.MTV, 1e-5)
+
+	tr := &triangle.Triangle{{-1, 1.5}, {1, 1.5}, {0, 3}}
+	r = GJK(a, NewHull(tr))
+	assert.False(t, r.Overlap)
+	assert.InDelta(t, 0.5, r.Distance, 1e-6)
+	geomtest.EqualInDelta(t, d2.Pt{0, 1.5}, r.B, 1e-5)
+}
+
+func TestTouching(t *testing.T) {
+	a := &triangle.Triangle{{0, 0}, {1, 0}, {0, 1}}
+	b := &triangle.Triangle{{1, 0}, {2, 0}, {2, 1}}
+	for _, r := range []Result{Collide(a, b), GJK(NewHull(a), NewHull(b))} {
+		assert.True(t, r.Overlap)
+		assert.InDelta(t, 0, r.Depth(), 1e-9)
+	}
+}
+
+func randomConvex(r *rand.Rand, c d2.Pt) polygon.Polygon {
+	pts := make([]d2.Pt, 3+r.Intn(8))
+	for i := range pts {
+		pts[i] = d2.Pt{c.X + r.Float64()*2 - 1, c.Y + r.Float64()*2 - 1}
+	}
+	return polygon.Polygon(polygon.ConvexHull(pts...))
+}
+
+// distance between two convex polygons that do not overlap.
+func distance(a, b polygon.Polygon) float64 {
+	d := math.Inf(1)
+	for _, pts := range [2][2]polygon.Polygon{{a, b}, {b, a}} {
+		for _, pt := range pts[0] {
+			d = math.Min(d, pts[1].Closest(pt).Distance(pt))
+		}
+	}
+	return d
+}
+
+func translate(p polygon.Polygon, v d2.V) polygon.Polygon {
+	out := make(polygon.Polygon, len(p))
+	for i, pt := range p {
+		out[i] = pt.Add(v)
+	}
+	return out
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(4242))
+	overlaps := 0
+	for i := 0; i < 200; i++ {
+		a := randomConvex(r, d2.Pt{})
+		b := randomConvex(r, d2.Pt{r.Float64()*3 - 1.5, r.Float64()*3 - 1.5})
+		if i%2 == 0 {
+			b = b.Reverse()
+		}
+		sat := Collide(a, b)
+		gjk := GJK(Hull(a), Hull(b))
+		assert.Equal(t, sat.Overlap, gjk.Overlap)
+		if !sat.Overlap {
+			assert.InDelta(t, distance(a, b), gjk.Distance, 1e-6)
+			assert.InDelta(t, gjk.Distance, sat.Distance, 1e-9)
+			assert.InDelta(t, gjk.Distance, gjk.A.Distance(gjk.B), 1e-9)
+			continue
+		}
+		overlaps++
+		assert.InDelta(t, sat.Depth(), gjk.Depth(), 1e-6)
+		for _, c := range []Result{sat, gjk} {
+			geomtest.EqualInDelta(t, c.MTV, c.A.Subtract(c.B), 1e-6)
+			moved := translate(b, c.MTV.Multiply(1+1e-6))
+			assert.False(t, SAT(a, moved).Overlap)
+			moved = translate(b, c.MTV.Multiply(1-1e-3))
+			assert.True(t, SAT(a, moved).Overlap)
+		}
+	}
+	assert.True(t, overlaps > 20)
+}
@@ -0,0 +1,221 @@
+package delaunay
+
+import (
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+)
+
+// Constrain forces the edge between the points at a and b, relative to Pts, to
+// be in the triangulation. The triangles crossed by the edge are removed and
+// each side is re-triangulated so that the result is a constrained Delaunay
+// triangulation. If other points lie on the edge, it is split at those points.
+func (t *Triangulation) Constrain(a, b uint32) {
+	t.constrain(a+super, b+super)
+}
+
+func (t *Triangulation) constrain(a, b uint32) {
+	if a == b || t.hasEdge(a, b) {
+		return
+	}
+	if k, ok := t.between(a, b); ok {
+		t.constrain(a, k)
+		t.constrain(k, b)
+		return
+	}
+
+	pa, pb := t.pts[a], t.pts[b]
+	side := func(i uint32) float64 { return orient(pa, pb, t.pts[i]) }
+
+	// Find the triangle at a that the edge leaves through.
+	var crossed []int
+	var l, r uint32
+	for _, i := range t.around(a) {
+		tr := t.tris[i]
+		j := indexOf(tr.v, a)
+		u, v := tr.v[(j+1)%3], tr.v[(j+2)%3]
+		if side(u) < 0 && side(v) > 0 {
+			crossed = append(crossed, i)
+			l, r = v, u
+			break
+		}
+	}
+	if len(crossed) == 0 {
+		return
+	}
+
+	// Walk across the triangles until b is reached, collecting the vertices on
+	// each side in order.
+	left, right := []uint32{l}, []uint32{r}
+	for {
+		n, ok := t.edges[[2]uint32{l, r}]
+		if !ok {
+			return
+		}
+		crossed = append(crossed, n)
+		w := t.tris[n].v[(indexOf(t.tris[n].v, l)+2)%3]
+		if w == b {
+			break
+		}
+		if side(w) > 0 {
+			l = w
+			left = append(left, w)
+		} else {
+			r = w
+			right = append(right, w)
+		}
+	}
+
+	for _, i := range crossed {
+		t.removeTri(i)
+	}
+	t.fill(a, b, left)
+	t.fill(a, b, right)
+}
+
+// fill triangulates the pseudo-polygon formed by the edge a, b and the chain of
+// vertices on one side of it by choosing the vertex whose circumcircle with a
+// and b contains no other vertex of the chain.
+func (t *Triangulation) fill(a, b uint32, chain []uint32) {
+	if len(chain) == 0 {
+		return
+	}
+	ci := 0
+	for i := 1; i < len(chain); i++ {
+		pa, pb, pc := t.pts[a], t.pts[b], t.pts[chain[ci]]
+		if orient(pa, pb, pc) < 0 {
+			pa, pb = pb, pa
+		}
+		if inCircle(pa, pb, pc, t.pts[chain[i]]) {
+			ci = i
+		}
+	}
+	c := chain[ci]
+	t.addTri(a, b, c)
+	t.fill(a, c, chain[:ci])
+	t.fill(c, b, chain[ci+1:])
+}
+
+// around returns the triangles that have a as a vertex. It turns around a from
+// the last triangle added at a, only checking every triangle if that one has
+// since been removed.
+func (t *Triangulation) around(a uint32) []int {
+	start := t.at[a]
+	if start < 0 || t.tris[start].dead {
+		var out []int
+		for i, tr := range t.tris {
+			if !tr.dead && indexOf(tr.v, a) >= 0 {
+				out = append(out, i)
+			}
+		}
+		return out
+	}
+
+	out := []int{start}
+	// counter-clockwise across the side leading into a
+	for i := start; ; {
+		v := t.tris[i].v
+		n, ok := t.edges[[2]uint32{a, v[(indexOf(v, a)+2)%3]}]
+		if !ok {
+			break
+		}
+		if n == start {
+			return out
+		}
+		out = append(out, n)
+		i = n
+	}
+	// clockwise across the side leading out of a
+	for i := start; ; {
+		v := t.tris[i].v
+		n, ok := t.edges[[2]uint32{v[(indexOf(v, a)+1)%3], a}]
+		if !ok {
+			break
+		}
+		out = append(out, n)
+		i = n
+	}
+	return out
+}
+
+func (t *Triangulation) hasEdge(a, b uint32) bool {
+	_, ok := t.edges[[2]uint32{a, b}]
+	if !ok {
+		_, ok = t.edges[[2]uint32{b, a}]
+	}
+	return ok
+}
+
+// between returns the point closest to a that lies on the segment from a to b.
+func (t *Triangulation) between(a, b uint32) (uint32, bool) {
+	pa, pb := t.pts[a], t.pts[b]
+	d := pb.Subtract(pa)
+	m2 := d.Mag2()
+	best, bestT := uint32(0), 2.0
+	for i := uint32(super); i < uint32(len(t.pts)); i++ {
+		if i == a || i == b {
+			continue
+		}
+		v := t.pts[i].Subtract(pa)
+		s := v.Dot(d) / m2
+		if s <= 0 || s >= 1 || s >= bestT {
+			continue
+		}
+		if c := d.Cross(v); c*c <= 1e-20*m2*m2 {
+			best, bestT = i, s
+		}
+	}
+	return best, bestT < 2
+}
+
+func indexOf(v [3]uint32, i uint32) int {
+	for j, vi := range v {
+		if vi == i {
+			return j
+		}
+	}
+	return -1
+}
+
+// NewPolygon creates a constrained Delaunay triangulation of the polygon. The
+// sides of the polygon are constrained and the triangles outside the polygon
+// are removed.
+func NewPolygon(p polygon.Polygon) *Triangulation {
+	return NewRegion(polygon.Region{Outer: p})
+}
+
+// NewRegion creates a constrained Delaunay triangulation of the region. The
+// sides of all the rings are constrained and the triangles outside the region,
+// including those in the holes, are removed.
+func NewRegion(r polygon.Region) *Triangulation {
+	rings := r.Rings()
+	t := New(ringPts(rings)...)
+	for _, ring := range rings {
+		ln := len(ring)
+		for i, pt := range ring {
+			a, _ := t.Insert(pt)
+			b, _ := t.Insert(ring[(i+1)%ln])
+			t.Constrain(a, b)
+		}
+	}
+	for i, tr := range t.tris {
+		if tr.dead {
+			continue
+		}
+		c := d2.Pt{
+			X: (t.pts[tr.v[0]].X + t.pts[tr.v[1]].X + t.pts[tr.v[2]].X) / 3,
+			Y: (t.pts[tr.v[0]].Y + t.pts[tr.v[1]].Y + t.pts[tr.v[2]].Y) / 3,
+		}
+		if !r.Contains(c) {
+			t.removeTri(i)
+		}
+	}
+	return t
+}
+
+func ringPts(rings []polygon.Polygon) []d2.Pt {
+	var pts []d2.Pt
+	for _, r := range rings {
+		pts = append(pts, r...)
+	}
+	return pts
+}
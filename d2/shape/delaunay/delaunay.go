@@ -0,0 +1,218 @@
+// Package delaunay provides Delaunay triangulation, constrained Delaunay
+// triangulation and the dual Voronoi diagram.
+package delaunay
+
+import (
+	"github.com/adamcolton/geom/d2"
+)
+
+// SuperScale controls the size of the super triangle that initially contains
+// all the points relative to the extent of the points passed into New. Points
+// passed to Insert must lie within the super triangle.
+var SuperScale = 1e3
+
+// super is the number of points in the super triangle. They are stored at the
+// start of pts.
+const super = 3
+
+type tri struct {
+	v    [3]uint32
+	dead bool
+}
+
+// Triangulation is a Delaunay triangulation built incrementally with the
+// Bowyer-Watson algorithm. All triangles proceed counter-clockwise.
+type Triangulation struct {
+	pts   []d2.Pt
+	tris  []tri
+	edges map[[2]uint32]int
+	idx   map[d2.Pt]uint32
+	// at holds the last triangle added at each point and last holds the last
+	// triangle added. They are starting points for walking the triangulation.
+	at   []int
+	last int
+}
+
+// New creates a Delaunay triangulation of the points. Duplicate points are
+// only added once, so the indexes returned by Triangles are relative to Pts,
+// not to the slice passed in.
+func New(pts ...d2.Pt) *Triangulation {
+	min, max := d2.MinMax(pts...)
+	c := d2.Pt{(min.X + max.X) / 2, (min.Y + max.Y) / 2}
+	s := max.X - min.X
+	if h := max.Y - min.Y; h > s {
+		s = h
+	}
+	s = (s + 1) * SuperScale
+	t := &Triangulation{
+		pts: []d2.Pt{
+			{c.X - 2*s, c.Y - s},
+			{c.X + 2*s, c.Y - s},
+			{c.X, c.Y + 2*s},
+		},
+		edges: make(map[[2]uint32]int),
+		idx:   make(map[d2.Pt]uint32),
+		at:    make([]int, super),
+	}
+	t.addTri(0, 1, 2)
+	for _, pt := range pts {
+		t.Insert(pt)
+	}
+	return t
+}
+
+// Insert adds a point to the triangulation and returns its index. If the point
+// is already in the triangulation, the existing index is returned. If the
+// point is outside the super triangle, it is not added and false is returned.
+// Inserting points after adding constraints may remove constrained edges.
+func (t *Triangulation) Insert(pt d2.Pt) (uint32, bool) {
+	if i, ok := t.idx[pt]; ok {
+		return i - super, true
+	}
+	start := t.locate(pt)
+	if start < 0 {
+		return 0, false
+	}
+
+	p := uint32(len(t.pts))
+	t.pts = append(t.pts, pt)
+	t.at = append(t.at, -1)
+	t.idx[pt] = p
+
+	// Grow the cavity from the triangle containing pt through the neighbors
+	// whose circumcircle contains pt. This keeps the cavity connected.
+	cavity := []int{start}
+	in := map[int]bool{start: true}
+	for i := 0; i < len(cavity); i++ {
+		v := t.tris[cavity[i]].v
+		for j := 0; j < 3; j++ {
+			n, ok := t.edges[[2]uint32{v[(j+1)%3], v[j]}]
+			if !ok || in[n] {
+				continue
+			}
+			nv := t.tris[n].v
+			if inCircle(t.pts[nv[0]], t.pts[nv[1]], t.pts[nv[2]], pt) {
+				in[n] = true
+				cavity = append(cavity, n)
+			}
+		}
+	}
+
+	var boundary [][2]uint32
+	for _, ti := range cavity {
+		v := t.tris[ti].v
+		for j := 0; j < 3; j++ {
+			n, ok := t.edges[[2]uint32{v[(j+1)%3], v[j]}]
+			if !ok || !in[n] {
+				boundary = append(boundary, [2]uint32{v[j], v[(j+1)%3]})
+			}
+		}
+	}
+	for _, ti := range cavity {
+		t.removeTri(ti)
+	}
+	for _, e := range boundary {
+		t.addTri(e[0], e[1], p)
+	}
+	return p - super, true
+}
+
+// locate returns the index of a triangle containing pt or -1. It walks from
+// the last triangle added towards pt, crossing any side that pt is to the right
+// of. If the walk leaves the triangulation or does not arrive, which can
+// happen once constraints are added or triangles are removed, every triangle is
+// checked.
+func (t *Triangulation) locate(pt d2.Pt) int {
+	i := t.last
+	for steps := 0; steps < len(t.tris) && !t.tris[i].dead; steps++ {
+		v := t.tris[i].v
+		next := -1
+		for j := 0; j < 3; j++ {
+			if orient(t.pts[v[j]], t.pts[v[(j+1)%3]], pt) < 0 {
+				n, ok := t.edges[[2]uint32{v[(j+1)%3], v[j]}]
+				if !ok {
+					break
+				}
+				next = n
+				break
+			}
+		}
+		if next == -1 {
+			if t.contains(i, pt) {
+				return i
+			}
+			break
+		}
+		i = next
+	}
+	for i, tr := range t.tris {
+		if !tr.dead && t.contains(i, pt) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Triangulation) contains(i int, pt d2.Pt) bool {
+	v := t.tris[i].v
+	a, b, c := t.pts[v[0]], t.pts[v[1]], t.pts[v[2]]
+	return orient(a, b, pt) >= 0 && orient(b, c, pt) >= 0 && orient(c, a, pt) >= 0
+}
+
+func (t *Triangulation) addTri(a, b, c uint32) {
+	if orient(t.pts[a], t.pts[b], t.pts[c]) < 0 {
+		b, c = c, b
+	}
+	i := len(t.tris)
+	t.tris = append(t.tris, tri{v: [3]uint32{a, b, c}})
+	t.edges[[2]uint32{a, b}] = i
+	t.edges[[2]uint32{b, c}] = i
+	t.edges[[2]uint32{c, a}] = i
+	t.at[a], t.at[b], t.at[c] = i, i, i
+	t.last = i
+}
+
+func (t *Triangulation) removeTri(i int) {
+	tr := &t.tris[i]
+	tr.dead = true
+	for j := 0; j < 3; j++ {
+		e := [2]uint32{tr.v[j], tr.v[(j+1)%3]}
+		if t.edges[e] == i {
+			delete(t.edges, e)
+		}
+	}
+}
+
+// Pts returns the points in the triangulation.
+func (t *Triangulation) Pts() []d2.Pt {
+	return t.pts[super:]
+}
+
+// Triangles returns the index sets of the triangles relative to Pts. Each
+// triangle proceeds counter-clockwise.
+func (t *Triangulation) Triangles() [][3]uint32 {
+	var out [][3]uint32
+	for _, tr := range t.tris {
+		if tr.dead || tr.v[0] < super || tr.v[1] < super || tr.v[2] < super {
+			continue
+		}
+		out = append(out, [3]uint32{tr.v[0] - super, tr.v[1] - super, tr.v[2] - super})
+	}
+	return out
+}
+
+// orient is positive if a, b, c proceed counter-clockwise.
+func orient(a, b, c d2.Pt) float64 {
+	return b.Subtract(a).Cross(c.Subtract(a))
+}
+
+// inCircle returns true if d is strictly inside the circumcircle of the
+// counter-clockwise triangle a, b, c.
+func inCircle(a, b, c, d d2.Pt) bool {
+	ad, bd, cd := a.Subtract(d), b.Subtract(d), c.Subtract(d)
+	a2, b2, c2 := ad.Mag2(), bd.Mag2(), cd.Mag2()
+	det := ad.X*(bd.Y*c2-b2*cd.Y) -
+		ad.Y*(bd.X*c2-b2*cd.X) +
+		a2*(bd.X*cd.Y-bd.Y*cd.X)
+	return det > 0
+}
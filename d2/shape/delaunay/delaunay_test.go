@@ -0,0 +1,216 @@
+package delaunay
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func area(pts []d2.Pt, tris [][3]uint32) float64 {
+	var sum float64
+	for _, tr := range tris {
+		sum += (&triangle.Triangle{pts[tr[0]], pts[tr[1]], pts[tr[2]]}).SignedArea()
+	}
+	return sum
+}
+
+func hasEdge(tris [][3]uint32, a, b uint32) bool {
+	for _, tr := range tris {
+		for i := 0; i < 3; i++ {
+			u, v := tr[i], tr[(i+1)%3]
+			if (u == a && v == b) || (u == b && v == a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pts := make([]d2.Pt, 50)
+	for i := range pts {
+		pts[i] = d2.Pt{r.Float64() * 10, r.Float64() * 10}
+	}
+	d := New(pts...)
+	got := d.Pts()
+	assert.Len(t, got, len(pts))
+	tris := d.Triangles()
+
+	hull := polygon.Polygon(polygon.ConvexHull(pts...))
+	geomtest.EqualInDelta(t, hull.Area(), area(got, tris), 1e-9)
+	// Euler: 2n - 2 - h triangles
+	assert.Len(t, tris, 2*len(pts)-2-len(hull))
+
+	for _, tr := range tris {
+		a, b, c := got[tr[0]], got[tr[1]], got[tr[2]]
+		assert.True(t, orient(a, b, c) > 0)
+		for i, pt := range got {
+			if uint32(i) == tr[0] || uint32(i) == tr[1] || uint32(i) == tr[2] {
+				continue
+			}
+			assert.False(t, inCircle(a, b, c, pt))
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	var pts []d2.Pt
+	for x := 0.0; x < 3; x++ {
+		for y := 0.0; y < 3; y++ {
+			pts = append(pts, d2.Pt{x, y})
+		}
+	}
+	// duplicates are ignored
+	d := New(append(pts, d2.Pt{1, 1})...)
+	assert.Len(t, d.Pts(), 9)
+	tris := d.Triangles()
+	assert.Len(t, tris, 8)
+	geomtest.Equal(t, 4.0, area(d.Pts(), tris))
+
+	i, ok := d.Insert(d2.Pt{0.5, 0.5})
+	assert.True(t, ok)
+	assert.Equal(t, uint32(9), i)
+	assert.Len(t, d.Triangles(), 10)
+
+	_, ok = d.Insert(d2.Pt{1e9, 1e9})
+	assert.False(t, ok)
+}
+
+func randomPts(n int) []d2.Pt {
+	r := rand.New(rand.NewSource(2))
+	pts := make([]d2.Pt, n)
+	for i := range pts {
+		pts[i] = d2.Pt{r.Float64() * 100, r.Float64() * 100}
+	}
+	return pts
+}
+
+// star is a polygon with n points alternating between radius 2 and 3.
+func star(n int) polygon.Polygon {
+	p := make(polygon.Polygon, n)
+	for i := range p {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		p[i] = d2.Pt{math.Cos(a) * (2 + float64(i%2)), math.Sin(a) * (2 + float64(i%2))}
+	}
+	return p
+}
+
+func TestLarge(t *testing.T) {
+	pts := randomPts(20000)
+	d := New(pts...)
+	hull := polygon.ConvexHull(pts...)
+	assert.Len(t, d.Triangles(), 2*len(pts)-2-len(hull))
+
+	p := star(2000)
+	d = NewPolygon(p)
+	tris := d.Triangles()
+	assert.Len(t, tris, len(p)-2)
+	geomtest.EqualInDelta(t, p.Area(), area(d.Pts(), tris), 1e-9)
+}
+
+func BenchmarkNew(b *testing.B) {
+	pts := randomPts(20000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(pts...)
+	}
+}
+
+func BenchmarkNewPolygon(b *testing.B) {
+	p := star(2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewPolygon(p)
+	}
+}
+
+func TestConstrain(t *testing.T) {
+	d := New(d2.Pt{0, 0}, d2.Pt{4, 0}, d2.Pt{2, 1}, d2.Pt{2, -1})
+	tris := d.Triangles()
+	assert.True(t, hasEdge(tris, 2, 3))
+	assert.False(t, hasEdge(tris, 0, 1))
+
+	d.Constrain(0, 1)
+	tris = d.Triangles()
+	assert.Len(t, tris, 2)
+	assert.True(t, hasEdge(tris, 0, 1))
+	assert.False(t, hasEdge(tris, 2, 3))
+	geomtest.Equal(t, 4.0, area(d.Pts(), tris))
+
+	// A point on the constraint splits it.
+	d = New(d2.Pt{0, 0}, d2.Pt{4, 0}, d2.Pt{2, 0}, d2.Pt{1, 1}, d2.Pt{3, -1})
+	d.Constrain(0, 1)
+	tris = d.Triangles()
+	assert.True(t, hasEdge(tris, 0, 2))
+	assert.True(t, hasEdge(tris, 2, 1))
+}
+
+func TestNewPolygon(t *testing.T) {
+	// comb shape that is not Delaunay on its own
+	p := polygon.Polygon{
+		{0, 0}, {6, 0}, {6, 3}, {5, 3}, {5, 1}, {4, 1}, {4, 3},
+		{3, 3}, {3, 1}, {2, 1}, {2, 3}, {1, 3}, {1, 1}, {0, 1},
+	}
+	d := NewPolygon(p)
+	tris := d.Triangles()
+	assert.Len(t, tris, len(p)-2)
+	geomtest.Equal(t, p.Area(), area(d.Pts(), tris))
+	for i := range p {
+		assert.True(t, hasEdge(tris, uint32(i), uint32((i+1)%len(p))))
+	}
+
+	o := polygon.NewRegion(
+		polygon.Polygon{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		polygon.Polygon{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+	)
+	d = NewRegion(o)
+	tris = d.Triangles()
+	assert.Len(t, tris, 8)
+	geomtest.Equal(t, 12.0, area(d.Pts(), tris))
+}
+
+func TestVoronoi(t *testing.T) {
+	var pts []d2.Pt
+	for x := 0.0; x < 3; x++ {
+		for y := 0.0; y < 3; y++ {
+			pts = append(pts, d2.Pt{x, y})
+		}
+	}
+	d := New(pts...)
+	cells := d.Voronoi(&box.Box{{-0.5, -0.5}, {2.5, 2.5}})
+	assert.Len(t, cells, 9)
+	for i, c := range cells {
+		geomtest.EqualInDelta(t, 1.0, c.SignedArea(), 1e-9)
+		geomtest.Equal(t, pts[i], c.Centroid())
+		assert.True(t, c.Contains(pts[i]))
+	}
+
+	r := rand.New(rand.NewSource(2))
+	pts = make([]d2.Pt, 30)
+	for i := range pts {
+		pts[i] = d2.Pt{r.Float64() * 10, r.Float64() * 10}
+	}
+	b := &box.Box{{0, 0}, {10, 10}}
+	cells = New(pts...).Voronoi(b)
+	var sum float64
+	for i, c := range cells {
+		sum += c.Area()
+		assert.True(t, c.Contains(pts[i]))
+		// each point is closest to its own site
+		for _, v := range c {
+			dv := v.Distance(pts[i])
+			for _, pt := range pts {
+				assert.True(t, v.Distance(pt) > dv-1e-6)
+			}
+		}
+	}
+	geomtest.EqualInDelta(t, 100.0, sum, 1e-6)
+}
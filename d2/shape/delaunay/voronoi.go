@@ -0,0 +1,48 @@
+package delaunay
+
+import (
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/d2/shape/triangle"
+)
+
+// Voronoi returns the Voronoi cell of each point clipped to the box. The cells
+// are indexed the same as Pts and proceed counter-clockwise. A cell that falls
+// entirely outside the box is nil. The cells are the dual of the triangulation
+// so this is only a true Voronoi diagram if no constraints were added.
+//
+// The triangles connected to the super triangle are used to close the cells of
+// the points on the convex hull. This is accurate as long as the box is not
+// much larger than the extent of the points, see SuperScale.
+func (t *Triangulation) Voronoi(b *box.Box) []polygon.Polygon {
+	around := make([][]d2.Pt, len(t.pts))
+	for _, tr := range t.tris {
+		if tr.dead {
+			continue
+		}
+		c := (&triangle.Triangle{t.pts[tr.v[0]], t.pts[tr.v[1]], t.pts[tr.v[2]]}).CircumCenter()
+		for _, v := range tr.v {
+			around[v] = append(around[v], c)
+		}
+	}
+
+	clip := polygon.Polygon(b.ConvexHull())
+	out := make([]polygon.Polygon, len(t.pts)-super)
+	for i := range out {
+		pt := t.pts[i+super]
+		cell := around[i+super]
+		if len(cell) < 3 {
+			continue
+		}
+		sort.Slice(cell, func(i, j int) bool {
+			return cell[i].Subtract(pt).Angle() < cell[j].Subtract(pt).Angle()
+		})
+		if ps := polygon.Polygon(cell).Intersection(clip); len(ps) > 0 {
+			out[i] = ps[0]
+		}
+	}
+	return out
+}
@@ -201,3 +201,27 @@ var ConvexHullPoints = 12
 func (e Ellipse) ConvexHull() []d2.Pt {
 	return e.PtApprox(ConvexHullPoints)
 }
+
+// Pt1Segment fulfills d2.Segmenter returning the range [t0, t1] of the
+// perimeter.
+func (e Ellipse) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return e.perimeter.Segment(t0, t1)
+}
+
+// Pt1Reverse fulfills d2.Reverser returning the perimeter traced in the
+// opposite direction.
+func (e Ellipse) Pt1Reverse() d2.Pt1 {
+	return e.perimeter.Reverse()
+}
+
+// T returns the Ellipse with the transform applied.
+func (e Ellipse) T(t *d2.T) Ellipse {
+	return Ellipse{
+		perimeter: e.perimeter.T(t),
+	}
+}
+
+// Pt1T fulfills d2.Transformer.
+func (e Ellipse) Pt1T(t *d2.T) d2.Pt1 {
+	return e.T(t)
+}
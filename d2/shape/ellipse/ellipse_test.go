@@ -124,3 +124,18 @@ func TestConvexHullPoints(t *testing.T) {
 
 	geomtest.Equal(t, a, e)
 }
+
+func TestSegmentReverseT(t *testing.T) {
+	e := New(d2.Pt{0, 0}, d2.Pt{2, 0}, 1)
+	s := e.Pt1Segment(0.25, 0.5)
+	r := e.Pt1Reverse()
+	tr := &d2.T{{1, 0.5, 2}, {-1, 1, 3}, {0, 0, 1}}
+	et := e.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, e.Pt1(0.25+i*0.25), s.Pt1(i))
+		geomtest.Equal(t, e.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(e.Pt1(i)), et.Pt1(i))
+	}
+	assert.True(t, et.Contains(tr.Pt(d2.Pt{1, 0})))
+	assert.False(t, et.Contains(tr.Pt(d2.Pt{1, 1.1})))
+}
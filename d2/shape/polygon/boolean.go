@@ -0,0 +1,349 @@
+package polygon
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/calc/cmpr"
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// BooleanTolerance is the distance at which points are treated as the same
+// point and a point is treated as lying on an edge by the boolean operations.
+var BooleanTolerance = cmpr.Tolerance(1e-9)
+
+// Union of two polygons. The result may have multiple pieces. Outer boundaries
+// proceed counter-clockwise and holes proceed clockwise.
+func (p Polygon) Union(p2 Polygon) []Polygon {
+	return boolean(p, p2, opUnion)
+}
+
+// Intersection of two polygons. The result may have multiple pieces. Outer
+// boundaries proceed counter-clockwise and holes proceed clockwise.
+func (p Polygon) Intersection(p2 Polygon) []Polygon {
+	return boolean(p, p2, opIntersection)
+}
+
+// Difference returns the region inside p and outside p2. The result may have
+// multiple pieces. Outer boundaries proceed counter-clockwise and holes proceed
+// clockwise.
+func (p Polygon) Difference(p2 Polygon) []Polygon {
+	return boolean(p, p2, opDifference)
+}
+
+// Xor returns the region inside exactly one of the polygons. It is the
+// Difference of p and p2 combined with the Difference of p2 and p. Outer
+// boundaries proceed counter-clockwise and holes proceed clockwise.
+func (p Polygon) Xor(p2 Polygon) []Polygon {
+	return append(p.Difference(p2), p2.Difference(p)...)
+}
+
+type booleanOp byte
+
+const (
+	opUnion booleanOp = iota
+	opIntersection
+	opDifference
+)
+
+// edgeClass describes where a piece of one polygon lies relative to the other.
+type edgeClass byte
+
+const (
+	edgeOutside edgeClass = iota
+	edgeInside
+	edgeSame     // shared edge proceeding in the same direction
+	edgeOpposite // shared edge proceeding in the opposite direction
+)
+
+type edge struct {
+	from, to d2.Pt
+}
+
+// boolean splits the edges of both polygons where they meet, classifies each
+// piece against the other polygon and then links the pieces selected by op
+// into rings. Each step is a sweep or a lookup so this takes O((n+k) log n)
+// time for n sides with k crossings.
+func boolean(a, b Polygon, op booleanOp) []Polygon {
+	if len(a) < 3 || len(b) < 3 {
+		return trivialBoolean(a, b, op)
+	}
+	if a.SignedArea() < 0 {
+		a = a.Reverse()
+	}
+	if b.SignedArea() < 0 {
+		b = b.Reverse()
+	}
+
+	rings := splitRings([]Polygon{a, b})
+	aEdges, bEdges := rings[0], rings[1]
+	var edges []edge
+	for i, c := range classify(aEdges, bEdges) {
+		e := aEdges[i]
+		switch c {
+		case edgeOutside:
+			if op == opUnion || op == opDifference {
+				edges = append(edges, e)
+			}
+		case edgeInside:
+			if op == opIntersection {
+				edges = append(edges, e)
+			}
+		case edgeSame:
+			if op != opDifference {
+				edges = append(edges, e)
+			}
+		case edgeOpposite:
+			if op == opDifference {
+				edges = append(edges, e)
+			}
+		}
+	}
+	for i, c := range classify(bEdges, aEdges) {
+		e := bEdges[i]
+		switch c {
+		case edgeOutside:
+			if op == opUnion {
+				edges = append(edges, e)
+			}
+		case edgeInside:
+			if op == opIntersection {
+				edges = append(edges, e)
+			} else if op == opDifference {
+				edges = append(edges, edge{e.to, e.from})
+			}
+		}
+	}
+	return linkRings(edges)
+}
+
+// trivialBoolean handles polygons that do not enclose any area.
+func trivialBoolean(a, b Polygon, op booleanOp) []Polygon {
+	aOk, bOk := len(a) >= 3, len(b) >= 3
+	switch {
+	case op == opUnion && aOk:
+		return []Polygon{append(Polygon(nil), a...)}
+	case op == opUnion && bOk:
+		return []Polygon{append(Polygon(nil), b...)}
+	case op == opDifference && aOk:
+		return []Polygon{append(Polygon(nil), a...)}
+	}
+	return nil
+}
+
+// split is a point on a side of a polygon.
+type split struct {
+	t  float64
+	pt d2.Pt
+}
+
+// onSide returns the parametric value of pt on s if it lies on s.
+func onSide(s line.Line, pt d2.Pt) (float64, bool) {
+	m2 := s.D.Mag2()
+	if m2 == 0 {
+		return 0, false
+	}
+	t := s.D.Dot(pt.Subtract(s.T0)) / m2
+	if t < 0 || t > 1 {
+		return 0, false
+	}
+	return t, BooleanTolerance.Zero(s.Pt1(t).Distance(pt))
+}
+
+// splitEdges breaks each side of p at the split points.
+func splitEdges(p Polygon, splits [][]split) []edge {
+	var out []edge
+	ln := len(p)
+	for i, from := range p {
+		to := p[(i+1)%ln]
+		ss := splits[i]
+		sort.Slice(ss, func(i, j int) bool { return ss[i].t < ss[j].t })
+		prev := from
+		for _, s := range ss {
+			if samePt(prev, s.pt) || samePt(to, s.pt) {
+				continue
+			}
+			out = append(out, edge{prev, s.pt})
+			prev = s.pt
+		}
+		if !samePt(prev, to) {
+			out = append(out, edge{prev, to})
+		}
+	}
+	return out
+}
+
+func samePt(a, b d2.Pt) bool {
+	return BooleanTolerance.Zero(a.Distance(b))
+}
+
+// pointSet merges points within BooleanTolerance of each other so that edges
+// that meet at nearly the same point link up exactly. Points are hashed into a
+// grid of cells twice the size of the tolerance so only the cell holding a
+// point and the three cells next to the nearest corner are checked.
+type pointSet struct {
+	pts   []d2.Pt
+	cells map[[2]float64][]int
+	// exact holds the result for points already passed to canonical
+	exact map[d2.Pt]d2.Pt
+}
+
+func newPointSet() *pointSet {
+	return &pointSet{
+		cells: make(map[[2]float64][]int),
+		exact: make(map[d2.Pt]d2.Pt),
+	}
+}
+
+// canonical returns the first point added to the set that is within
+// BooleanTolerance of pt. If there is none, pt is added.
+func (ps *pointSet) canonical(pt d2.Pt) d2.Pt {
+	if c, ok := ps.exact[pt]; ok {
+		return c
+	}
+	s := 2 * float64(BooleanTolerance)
+	x, y := pt.X/s, pt.Y/s
+	cx, cy := math.Floor(x), math.Floor(y)
+	nx, ny := cx+1, cy+1
+	if x-cx < 0.5 {
+		nx = cx - 1
+	}
+	if y-cy < 0.5 {
+		ny = cy - 1
+	}
+	best := -1
+	for _, c := range [4][2]float64{{cx, cy}, {nx, cy}, {cx, ny}, {nx, ny}} {
+		for _, i := range ps.cells[c] {
+			if (best == -1 || i < best) && samePt(ps.pts[i], pt) {
+				best = i
+			}
+		}
+	}
+	out := pt
+	if best != -1 {
+		out = ps.pts[best]
+	} else {
+		c := [2]float64{cx, cy}
+		ps.cells[c] = append(ps.cells[c], len(ps.pts))
+		ps.pts = append(ps.pts, pt)
+	}
+	ps.exact[pt] = out
+	return out
+}
+
+// edges replaces the end points of the edges with their canonical points.
+// Edges that start and end at the same point are removed.
+func (ps *pointSet) edges(es []edge) []edge {
+	out := es[:0]
+	for _, e := range es {
+		e = edge{ps.canonical(e.from), ps.canonical(e.to)}
+		if e.from != e.to {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// classify each of the edges es relative to the ring formed by the edges p by
+// checking its midpoint. Both must come from the same call to splitRings so
+// that shared edges have the same end points.
+func classify(es, p []edge) []edgeClass {
+	onP := make(map[edge]bool, len(p))
+	for _, e := range p {
+		onP[e] = true
+	}
+	out := make([]edgeClass, len(es))
+	var idxs []int
+	var mids []d2.Pt
+	for i, e := range es {
+		switch {
+		case onP[e]:
+			out[i] = edgeSame
+		case onP[edge{e.to, e.from}]:
+			out[i] = edgeOpposite
+		default:
+			idxs = append(idxs, i)
+			mids = append(mids, line.New(e.from, e.to).Pt1(0.5))
+		}
+	}
+	for i, w := range windingsAt(p, mids) {
+		if w != 0 {
+			out[idxs[i]] = edgeInside
+		}
+	}
+	return out
+}
+
+// linkRings joins edges end to end into rings. Where more than one edge leaves
+// a point, the edge with the smallest clockwise turn from the reverse of the
+// incoming edge is chosen. This keeps pieces that only touch at a point
+// separate. The end points of the edges must match exactly, see pointSet. A
+// chain of edges that does not get back to its start is closed with a side from
+// its last point to its first so the edges are not lost.
+func linkRings(edges []edge) []Polygon {
+	used := make([]bool, len(edges))
+	byStart := make(map[d2.Pt][]int)
+	for i, e := range edges {
+		byStart[e.from] = append(byStart[e.from], i)
+	}
+	next := func(cur edge) int {
+		back := cur.from.Subtract(cur.to)
+		best, bestA := -1, 0.0
+		for _, i := range byStart[cur.to] {
+			if used[i] {
+				continue
+			}
+			d := edges[i].to.Subtract(edges[i].from)
+			// clockwise angle from back to d in (0, 2pi]
+			a := math.Atan2(-back.Cross(d), back.Dot(d))
+			if a <= 0 {
+				a += 2 * math.Pi
+			}
+			if best == -1 || a < bestA {
+				best, bestA = i, a
+			}
+		}
+		return best
+	}
+
+	var out []Polygon
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		ring := Polygon{edges[start].from}
+		cur := edges[start]
+		for cur.to != edges[start].from {
+			ring = append(ring, cur.to)
+			i := next(cur)
+			if i == -1 {
+				break
+			}
+			used[i] = true
+			cur = edges[i]
+		}
+		if ring = removeCollinear(ring); len(ring) >= 3 {
+			out = append(out, ring)
+		}
+	}
+	return out
+}
+
+// removeCollinear removes points that lie on the line between their neighbors.
+func removeCollinear(p Polygon) Polygon {
+	for changed := true; changed && len(p) >= 3; {
+		changed = false
+		ln := len(p)
+		for i := range p {
+			prev, cur, nxt := p[(i+ln-1)%ln], p[i], p[(i+1)%ln]
+			if _, ok := onSide(line.New(prev, nxt), cur); ok {
+				p = append(p[:i], p[i+1:]...)
+				changed = true
+				break
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,176 @@
+package polygon
+
+import (
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func square(x, y, s float64) Polygon {
+	return Polygon{{x, y}, {x + s, y}, {x + s, y + s}, {x, y + s}}
+}
+
+func signedAreas(ps []Polygon) []float64 {
+	out := make([]float64, len(ps))
+	for i, p := range ps {
+		out[i] = p.SignedArea()
+	}
+	return out
+}
+
+func TestBooleanOverlap(t *testing.T) {
+	a, b := square(0, 0, 2), square(1, 1, 2)
+
+	u := a.Union(b)
+	if assert.Len(t, u, 1) {
+		assert.Len(t, u[0], 8)
+		geomtest.Equal(t, 7.0, u[0].SignedArea())
+	}
+
+	i := a.Intersection(b)
+	if assert.Len(t, i, 1) {
+		assert.Len(t, i[0], 4)
+		geomtest.Equal(t, 1.0, i[0].SignedArea())
+		assert.True(t, i[0].Contains(d2.Pt{1.5, 1.5}))
+	}
+
+	d := a.Difference(b)
+	if assert.Len(t, d, 1) {
+		assert.Len(t, d[0], 6)
+		geomtest.Equal(t, 3.0, d[0].SignedArea())
+		assert.True(t, d[0].Contains(d2.Pt{0.5, 1.5}))
+		assert.False(t, d[0].Contains(d2.Pt{1.5, 1.5}))
+	}
+
+	x := a.Xor(b)
+	geomtest.Equal(t, []float64{3, 3}, signedAreas(x))
+
+	// orientation of the input does not matter
+	geomtest.Equal(t, 7.0, a.Reverse().Union(b.Reverse())[0].SignedArea())
+}
+
+func TestBooleanContained(t *testing.T) {
+	outer, inner := square(0, 0, 4), square(1, 1, 2)
+
+	u := outer.Union(inner)
+	if assert.Len(t, u, 1) {
+		geomtest.Equal(t, 16.0, u[0].SignedArea())
+	}
+	i := outer.Intersection(inner)
+	if assert.Len(t, i, 1) {
+		geomtest.Equal(t, 4.0, i[0].SignedArea())
+	}
+
+	// the hole proceeds clockwise
+	d := outer.Difference(inner)
+	geomtest.Equal(t, []float64{16, -4}, signedAreas(d))
+	assert.Len(t, inner.Difference(outer), 0)
+}
+
+func TestBooleanSharedEdge(t *testing.T) {
+	a, b := square(0, 0, 1), Polygon{{1, 0}, {2, 0}, {2, 1}, {1, 1}}
+
+	u := a.Union(b)
+	if assert.Len(t, u, 1) {
+		assert.Len(t, u[0], 4)
+		geomtest.Equal(t, 2.0, u[0].SignedArea())
+	}
+	assert.Len(t, a.Intersection(b), 0)
+	d := a.Difference(b)
+	if assert.Len(t, d, 1) {
+		geomtest.Equal(t, 1.0, d[0].SignedArea())
+	}
+
+	// partially shared edge
+	c := Polygon{{1, 0.5}, {2, 0.5}, {2, 2}, {1, 2}}
+	u = a.Union(c)
+	if assert.Len(t, u, 1) {
+		assert.Len(t, u[0], 8)
+		geomtest.Equal(t, 2.5, u[0].SignedArea())
+	}
+
+	// identical
+	u = a.Union(a)
+	if assert.Len(t, u, 1) {
+		geomtest.Equal(t, 1.0, u[0].SignedArea())
+	}
+	i := a.Intersection(a)
+	if assert.Len(t, i, 1) {
+		geomtest.Equal(t, 1.0, i[0].SignedArea())
+	}
+	assert.Len(t, a.Difference(a), 0)
+}
+
+func TestBooleanMultiplePieces(t *testing.T) {
+	u := Polygon{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	bar := Polygon{{-1, 2}, {4, 2}, {4, 2.5}, {-1, 2.5}}
+
+	i := u.Intersection(bar)
+	geomtest.Equal(t, []float64{0.5, 0.5}, signedAreas(i))
+
+	d := u.Difference(bar)
+	geomtest.Equal(t, 6.0, sum(signedAreas(d)))
+	assert.Len(t, d, 3)
+
+	// touching at a corner stays as two pieces
+	c := square(0, 0, 1).Union(square(1, 1, 1))
+	geomtest.Equal(t, []float64{1, 1}, signedAreas(c))
+
+	// disjoint
+	assert.Len(t, square(0, 0, 1).Union(square(5, 5, 1)), 2)
+	assert.Len(t, square(0, 0, 1).Intersection(square(5, 5, 1)), 0)
+}
+
+func sum(fs []float64) float64 {
+	var s float64
+	for _, f := range fs {
+		s += f
+	}
+	return s
+}
+
+func TestBooleanAreas(t *testing.T) {
+	a := RegularPolygonRadius(d2.Pt{0, 0}, 3, 0, 7)
+	b := RegularPolygonRadius(d2.Pt{1, 2}, 2.5, 0.3, 5)
+	u := sum(signedAreas(a.Union(b)))
+	i := sum(signedAreas(a.Intersection(b)))
+	d := sum(signedAreas(a.Difference(b)))
+	x := sum(signedAreas(a.Xor(b)))
+	geomtest.Equal(t, a.Area()+b.Area(), u+i)
+	geomtest.Equal(t, a.Area(), d+i)
+	geomtest.Equal(t, u-i, x)
+}
+
+func TestLinkRingsOpen(t *testing.T) {
+	closed := []edge{{d2.Pt{0, 0}, d2.Pt{1, 0}}, {d2.Pt{1, 0}, d2.Pt{1, 1}}, {d2.Pt{1, 1}, d2.Pt{0, 0}}}
+	open := []edge{{d2.Pt{5, 0}, d2.Pt{6, 0}}, {d2.Pt{6, 0}, d2.Pt{6, 1}}, {d2.Pt{6, 1}, d2.Pt{5, 1}}}
+	ps := linkRings(append(open, closed...))
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, 1.0, ps[0].SignedArea())
+		geomtest.Equal(t, 0.5, ps[1].SignedArea())
+	}
+	geomtest.Equal(t, []Polygon{{{5, 0}, {6, 0}, {6, 1}, {5, 1}}}, linkRings(open))
+}
+
+func TestBooleanNearlySharedSide(t *testing.T) {
+	// the sides at x=2 are within BooleanTolerance but not equal
+	a := Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	b := Polygon{{2 + 1e-11, 0}, {4, 0}, {4, 2}, {2 + 1e-11, 2}}
+	u := a.Union(b)
+	if assert.Len(t, u, 1) {
+		geomtest.Equal(t, 8.0, u[0].SignedArea())
+		assert.Len(t, u[0], 4)
+	}
+	assert.Len(t, a.Intersection(b), 0)
+	d := a.Difference(b)
+	if assert.Len(t, d, 1) {
+		geomtest.Equal(t, 4.0, d[0].SignedArea())
+	}
+
+	pts := newPointSet()
+	assert.Equal(t, d2.Pt{2, 0}, pts.canonical(d2.Pt{2, 0}))
+	assert.Equal(t, d2.Pt{2, 0}, pts.canonical(d2.Pt{2 + 1e-11, -1e-11}))
+	assert.Equal(t, d2.Pt{2, 1e-8}, pts.canonical(d2.Pt{2, 1e-8}))
+}
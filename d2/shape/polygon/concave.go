@@ -1,6 +1,9 @@
 package polygon
 
 import (
+	"math"
+	"sort"
+
 	"github.com/adamcolton/geom/d2"
 	"github.com/adamcolton/geom/d2/curve/line"
 	"github.com/adamcolton/geom/d2/shape/triangle"
@@ -11,6 +14,10 @@ type ConcavePolygon struct {
 	concave   Polygon
 	regular   Polygon
 	triangles [][2]*triangle.Triangle
+	region    *Region
+	pieces    []Polygon
+	// areas is the cumulative area of the pieces
+	areas []float64
 }
 
 // GetTriangles takes triangle indexes from FindTriangles and returns a slice
@@ -36,11 +43,43 @@ func NewConcavePolygon(concave Polygon) ConcavePolygon {
 		ts[i][1] = cts[i]
 	}
 
-	return ConcavePolygon{
+	c := ConcavePolygon{
 		concave:   concave,
 		regular:   regular,
 		triangles: ts,
 	}
+	return c.WithPieces(hertelMehlhorn(concave, tIdxs))
+}
+
+// WithPieces sets the convex pieces used by Pieces, PiecesContain and AreaPt2.
+// The pieces are index sets from ConvexDecomposition or
+// OptimalConvexDecomposition. NewConcavePolygon uses ConvexDecomposition.
+func (c ConcavePolygon) WithPieces(pieces [][]uint32) ConcavePolygon {
+	c.pieces = make([]Polygon, len(pieces))
+	c.areas = make([]float64, len(pieces))
+	var sum float64
+	for i, idxs := range pieces {
+		pc := make(Polygon, len(idxs))
+		for j, idx := range idxs {
+			pc[j] = c.concave[idx]
+		}
+		c.pieces[i] = pc
+		sum += pc.Area()
+		c.areas[i] = sum
+	}
+	return c
+}
+
+// Pieces returns the convex pieces that make up the polygon. Algorithms that
+// only work on convex polygons can be applied to each piece.
+func (c ConcavePolygon) Pieces() []Polygon { return c.pieces }
+
+// NewConcaveRegion converts a Region to a ConcavePolygon. The holes are joined
+// to the outer ring using Region.Polygon.
+func NewConcaveRegion(r Region) ConcavePolygon {
+	c := NewConcavePolygon(r.Polygon())
+	c.region = &r
+	return c
 }
 
 // Pt2 returns a point in the ConcavePolygon adhereing to the shape rules
@@ -68,6 +107,47 @@ func (c ConcavePolygon) Pt2(t0, t1 float64) d2.Pt {
 	return d2.Pt{}
 }
 
+// AreaPt2 returns a point in the ConcavePolygon. Unlike Pt2, equal areas of the
+// parametric space map to equal areas of the polygon, so uniformly distributed
+// values of t0 and t1 produce uniformly distributed points. t0 selects a piece
+// and a triangle within it by area.
+func (c ConcavePolygon) AreaPt2(t0, t1 float64) d2.Pt {
+	if len(c.pieces) == 0 {
+		return d2.Pt{}
+	}
+	total := c.areas[len(c.areas)-1]
+	a := t0 * total
+	i := sort.SearchFloat64s(c.areas, a)
+	if i == len(c.areas) {
+		i--
+	}
+	pc := c.pieces[i]
+	var prev float64
+	if i > 0 {
+		prev = c.areas[i-1]
+	}
+
+	// fan of triangles from the first point
+	a -= prev
+	var j int
+	var ta float64
+	for j = 1; j < len(pc)-2; j++ {
+		ta = (&triangle.Triangle{pc[0], pc[j], pc[j+1]}).Area()
+		if a <= ta {
+			break
+		}
+		a -= ta
+	}
+	ta = (&triangle.Triangle{pc[0], pc[j], pc[j+1]}).Area()
+	u := 0.0
+	if ta > 0 {
+		u = math.Min(a/ta, 1)
+	}
+	su := math.Sqrt(u)
+	o := pc[0]
+	return o.Add(pc[j].Subtract(o).Multiply(su * (1 - t1))).Add(pc[j+1].Subtract(o).Multiply(su * t1))
+}
+
 // Pt1 returns a point on the perimeter
 func (c ConcavePolygon) Pt1(t0 float64) d2.Pt { return c.concave.Pt1(t0) }
 
@@ -78,10 +158,59 @@ func (c ConcavePolygon) Area() float64 { return c.concave.Area() }
 func (c ConcavePolygon) SignedArea() float64 { return c.concave.SignedArea() }
 
 // Perimeter returns the total length of the perimeter
-func (c ConcavePolygon) Perimeter() float64 { return c.concave.Perimeter() }
+func (c ConcavePolygon) Perimeter() float64 {
+	if c.region != nil {
+		return c.region.Perimeter()
+	}
+	return c.concave.Perimeter()
+}
 
 // Contains returns true of the point f is inside of the polygon
-func (c ConcavePolygon) Contains(f d2.Pt) bool { return c.concave.Contains(f) }
+func (c ConcavePolygon) Contains(f d2.Pt) bool {
+	if c.region != nil {
+		return c.region.Contains(f)
+	}
+	return c.concave.Contains(f)
+}
+
+// PiecesContain returns true if pt is inside or on the perimeter of one of the
+// convex pieces.
+func (c ConcavePolygon) PiecesContain(pt d2.Pt) bool {
+	for _, pc := range c.pieces {
+		if convexContains(pc, pt) {
+			return true
+		}
+	}
+	return false
+}
 
 // Centroid returns the center of mass of the polygon
 func (c ConcavePolygon) Centroid() d2.Pt { return c.concave.Centroid() }
+
+// LineIntersections fulfills line.Intersector
+func (c ConcavePolygon) LineIntersections(l line.Line, buf []float64) []float64 {
+	if c.region != nil {
+		return c.region.LineIntersections(l, buf)
+	}
+	return c.concave.LineIntersections(l, buf)
+}
+
+// ConvexHull fulfills shape.ConvexHuller
+func (c ConcavePolygon) ConvexHull() []d2.Pt { return c.concave.ConvexHull() }
+
+// convexContains returns true if pt is inside or on the perimeter of the convex
+// polygon p.
+func convexContains(p Polygon, pt d2.Pt) bool {
+	var pos, neg bool
+	prev := p[len(p)-1]
+	for _, cur := range p {
+		c := line.New(prev, cur).Cross(pt)
+		pos = pos || c > 0
+		neg = neg || c < 0
+		if pos && neg {
+			return false
+		}
+		prev = cur
+	}
+	return true
+}
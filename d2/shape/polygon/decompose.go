@@ -0,0 +1,337 @@
+package polygon
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// ConvexDecomposition breaks the polygon into convex pieces using the
+// Hertel-Mehlhorn algorithm. The polygon is triangulated with FindTriangles and
+// then each diagonal is removed if the two pieces it separates form a convex
+// piece. The result is never more than four times the optimal number of pieces.
+// Each piece is a list of indexes into p and proceeds in the same direction as
+// p. The polygon should not intersect itself.
+func (p Polygon) ConvexDecomposition() [][]uint32 {
+	if len(p) < 4 {
+		return [][]uint32{seq(0, len(p))}
+	}
+	return hertelMehlhorn(p, p.FindTriangles())
+}
+
+func seq(from, to int) []uint32 {
+	out := make([]uint32, 0, to-from)
+	for i := from; i < to; i++ {
+		out = append(out, uint32(i))
+	}
+	return out
+}
+
+// orientation is 1 if p proceeds counter-clockwise and -1 otherwise.
+func (p Polygon) orientation() float64 {
+	if p.SignedArea() < 0 {
+		return -1
+	}
+	return 1
+}
+
+// convexTurn returns true if the turn at b from a to c does not bend against
+// the orientation. Collinear points are treated as convex.
+func convexTurn(a, b, c d2.Pt, orientation float64) bool {
+	v0, v1 := b.Subtract(a), c.Subtract(b)
+	return orientation*v0.Cross(v1) >= -1e-9*v0.Mag()*v1.Mag()
+}
+
+func hertelMehlhorn(p Polygon, triangles [][3]uint32) [][]uint32 {
+	o := p.orientation()
+	pieces := make([][]uint32, len(triangles))
+	owner := make(map[[2]uint32]int)
+	for i, t := range triangles {
+		pieces[i] = []uint32{t[0], t[1], t[2]}
+		for j := range t {
+			owner[[2]uint32{t[j], t[(j+1)%3]}] = i
+		}
+	}
+
+	for _, t := range triangles {
+		for j := range t {
+			a, b := t[j], t[(j+1)%3]
+			pa, ok := owner[[2]uint32{a, b}]
+			if !ok || a > b {
+				continue
+			}
+			pb, ok := owner[[2]uint32{b, a}]
+			if !ok || pa == pb {
+				continue
+			}
+			merged := mergePieces(pieces[pa], pieces[pb], a, b)
+			if !convexAt(p, merged, a, o) || !convexAt(p, merged, b, o) {
+				continue
+			}
+			pieces[pa] = merged
+			pieces[pb] = nil
+			delete(owner, [2]uint32{a, b})
+			delete(owner, [2]uint32{b, a})
+			for k, idx := range merged {
+				e := [2]uint32{idx, merged[(k+1)%len(merged)]}
+				if _, ok := owner[e]; ok {
+					owner[e] = pa
+				}
+			}
+		}
+	}
+
+	var out [][]uint32
+	for _, pc := range pieces {
+		if pc != nil {
+			out = append(out, pc)
+		}
+	}
+	return out
+}
+
+// rotateTo returns the piece starting at idx.
+func rotateTo(piece []uint32, idx uint32) []uint32 {
+	for i, v := range piece {
+		if v == idx {
+			return append(append([]uint32(nil), piece[i:]...), piece[:i]...)
+		}
+	}
+	return nil
+}
+
+// mergePieces joins piece a, which has the side from i to j, with piece b,
+// which has the side from j to i.
+func mergePieces(a, b []uint32, i, j uint32) []uint32 {
+	ra, rb := rotateTo(a, j), rotateTo(b, i)
+	return append(ra, rb[1:len(rb)-1]...)
+}
+
+func convexAt(p Polygon, piece []uint32, idx uint32, o float64) bool {
+	ln := len(piece)
+	for i, v := range piece {
+		if v == idx {
+			return convexTurn(p[piece[(i+ln-1)%ln]], p[v], p[piece[(i+1)%ln]], o)
+		}
+	}
+	return true
+}
+
+// OptimalConvexDecomposition breaks the polygon into the fewest convex pieces
+// possible without adding vertices. This uses dynamic programming over the
+// diagonals of the polygon following Keil; for each diagonal only the
+// decompositions of the sub-polygon using the fewest pieces are kept along
+// with the narrowest angles they leave at the ends of the diagonal. It takes
+// O(n^3) time for polygons with few reflex vertices, ConvexDecomposition is
+// better suited to large polygons. Each piece is a list of indexes into p and
+// proceeds in the same direction as p. The polygon should not intersect itself.
+func (p Polygon) OptimalConvexDecomposition() [][]uint32 {
+	ln := len(p)
+	if ln < 4 {
+		return [][]uint32{seq(0, ln)}
+	}
+	k := &keil{
+		p: p,
+		o: p.orientation(),
+	}
+	k.res = make([][]*keilRes, ln)
+	for i := range k.res {
+		k.res[i] = make([]*keilRes, ln)
+	}
+	for gap := 2; gap < ln; gap++ {
+		for i := 0; i+gap < ln; i++ {
+			j := i + gap
+			if gap == ln-1 || p.diagonal(i, j) {
+				k.solve(i, j)
+			}
+		}
+	}
+	root := k.res[0][ln-1]
+	if root == nil || len(root.opts) == 0 {
+		return p.ConvexDecomposition()
+	}
+	var out [][]uint32
+	c := k.chain(0, ln-1, root.opts[0], &out)
+	return append(out, c)
+}
+
+// diagonal returns true if the segment from p[i] to p[j] lies inside the
+// polygon without touching any other vertex or crossing any side.
+func (p Polygon) diagonal(i, j int) bool {
+	d := line.New(p[i], p[j])
+	if d.D.X == 0 && d.D.Y == 0 {
+		return false
+	}
+	for k, pt := range p {
+		if k == i || k == j {
+			continue
+		}
+		if t, ok := onSide(d, pt); ok && t > 0 && t < 1 {
+			return false
+		}
+	}
+	ln := len(p)
+	for k, s := range p.Sides() {
+		if k == i || k == j || (k+1)%ln == i || (k+1)%ln == j {
+			// sides that share an end point can only touch the diagonal there
+			continue
+		}
+		t0, t1, ok := d.Intersection(s)
+		if ok && t0 > 0 && t0 < 1 && t1 > 0 && t1 < 1 {
+			return false
+		}
+	}
+	w, on := p.winding(d.Pt1(0.5))
+	return !on && w != 0
+}
+
+const (
+	keilEdge byte = iota
+	keilKept
+	keilMerged
+)
+
+// keilPart describes how one side of a piece is handled. A side that is a
+// diagonal is either kept, making the sub-polygon beyond it separate pieces, or
+// merged with the piece beyond it using the option at index opt.
+type keilPart struct {
+	kind byte
+	opt  int
+}
+
+// keilOpt is a decomposition of the sub-polygon from i to j. The piece on the
+// diagonal from i to j has the vertices i, s, ..., k, ..., t, j. The part from
+// i to k and the part from k to j describe how the rest of the sub-polygon is
+// handled.
+type keilOpt struct {
+	s, t, k int
+	a, b    keilPart
+}
+
+type keilRes struct {
+	w    int
+	opts []keilOpt
+}
+
+type keil struct {
+	p   Polygon
+	o   float64
+	res [][]*keilRes
+}
+
+// keilSide is a way of handling the sub-polygon from i to j when the side from
+// i to j is part of a piece. The piece continues from i to s and reaches j
+// from t.
+type keilSide struct {
+	keilPart
+	w, s, t int
+}
+
+func (k *keil) sides(i, j int) []keilSide {
+	if j == i+1 {
+		return []keilSide{{w: 0, s: j, t: i}}
+	}
+	r := k.res[i][j]
+	if r == nil {
+		return nil
+	}
+	out := []keilSide{{keilPart: keilPart{kind: keilKept}, w: r.w + 1, s: j, t: i}}
+	for idx, o := range r.opts {
+		out = append(out, keilSide{keilPart: keilPart{keilMerged, idx}, w: r.w, s: o.s, t: o.t})
+	}
+	return out
+}
+
+func (k *keil) solve(i, j int) {
+	p := k.p
+	r := &keilRes{w: math.MaxInt32}
+	for m := i + 1; m < j; m++ {
+		as, bs := k.sides(i, m), k.sides(m, j)
+		for _, a := range as {
+			if !convexTurn(p[j], p[i], p[a.s], k.o) {
+				continue
+			}
+			for _, b := range bs {
+				w := a.w + b.w
+				if w > r.w ||
+					!convexTurn(p[a.t], p[m], p[b.s], k.o) ||
+					!convexTurn(p[b.t], p[j], p[i], k.o) {
+					continue
+				}
+				if w < r.w {
+					r.w = w
+					r.opts = r.opts[:0]
+				}
+				r.opts = append(r.opts, keilOpt{
+					s: a.s,
+					t: b.t,
+					k: m,
+					a: a.keilPart,
+					b: b.keilPart,
+				})
+			}
+		}
+	}
+	if len(r.opts) > 0 {
+		r.opts = k.narrowest(i, j, r.opts)
+		k.res[i][j] = r
+	}
+}
+
+// narrowest removes the options that leave a wider angle at both i and j than
+// another option. A narrower angle can always be merged with the piece on the
+// other side of the diagonal if a wider angle can.
+func (k *keil) narrowest(i, j int, opts []keilOpt) []keilOpt {
+	p := k.p
+	vi, vj := p[j].Subtract(p[i]), p[i].Subtract(p[j])
+	type angles struct {
+		a, b float64
+		keilOpt
+	}
+	as := make([]angles, len(opts))
+	for n, o := range opts {
+		s, t := p[o.s].Subtract(p[i]), p[o.t].Subtract(p[j])
+		as[n] = angles{
+			a:       math.Atan2(k.o*s.Cross(vi), s.Dot(vi)),
+			b:       math.Atan2(k.o*vj.Cross(t), vj.Dot(t)),
+			keilOpt: o,
+		}
+	}
+	sort.SliceStable(as, func(x, y int) bool {
+		if as[x].a != as[y].a {
+			return as[x].a < as[y].a
+		}
+		return as[x].b < as[y].b
+	})
+	out := opts[:0]
+	minB := math.Inf(1)
+	for _, a := range as {
+		if a.b < minB-1e-12 {
+			minB = a.b
+			out = append(out, a.keilOpt)
+		}
+	}
+	return out
+}
+
+// chain returns the piece on the diagonal from i to j from i to j and adds any
+// pieces separated by kept diagonals to out.
+func (k *keil) chain(i, j int, o keilOpt, out *[][]uint32) []uint32 {
+	a := k.part(i, o.k, o.a, out)
+	b := k.part(o.k, j, o.b, out)
+	return append(a, b[1:]...)
+}
+
+func (k *keil) part(i, j int, pt keilPart, out *[][]uint32) []uint32 {
+	switch pt.kind {
+	case keilEdge:
+		return []uint32{uint32(i), uint32(j)}
+	case keilKept:
+		c := k.chain(i, j, k.res[i][j].opts[0], out)
+		*out = append(*out, c)
+		return []uint32{uint32(i), uint32(j)}
+	}
+	return k.chain(i, j, k.res[i][j].opts[pt.opt], out)
+}
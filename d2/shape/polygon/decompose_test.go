@@ -0,0 +1,133 @@
+package polygon
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/angle"
+	"github.com/adamcolton/geom/d2"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertDecomposition checks that the pieces are convex, use each index
+// correctly and cover the area of the polygon.
+func assertDecomposition(t *testing.T, p Polygon, pieces [][]uint32) {
+	var area float64
+	for _, pc := range pieces {
+		sub := make(Polygon, len(pc))
+		for i, idx := range pc {
+			sub[i] = p[idx]
+		}
+		o := p.orientation()
+		assert.True(t, o*sub.SignedArea() > 0)
+		for i := range sub {
+			a, b, c := sub[i], sub[(i+1)%len(sub)], sub[(i+2)%len(sub)]
+			assert.True(t, o*b.Subtract(a).Cross(c.Subtract(b)) >= -1e-9, "%v", pc)
+		}
+		area += sub.Area()
+	}
+	assert.InDelta(t, p.Area(), area, 1e-6)
+}
+
+func TestConvexDecomposition(t *testing.T) {
+	tt := map[string]struct {
+		Polygon
+		optimal int
+	}{
+		"square": {
+			Polygon: square(0, 0, 1),
+			optimal: 1,
+		},
+		"L": {
+			Polygon: Polygon{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}},
+			optimal: 2,
+		},
+		"plus": {
+			Polygon: Polygon{{1, 0}, {2, 0}, {2, 1}, {3, 1}, {3, 2}, {2, 2}, {2, 3}, {1, 3}, {1, 2}, {0, 2}, {0, 1}, {1, 1}},
+			optimal: 3,
+		},
+		"comb": {
+			Polygon: Polygon{{0, 0}, {7, 0}, {7, 2}, {6, 2}, {6, 1}, {5, 1}, {5, 2}, {4, 2}, {4, 1}, {3, 1}, {3, 2}, {2, 2}, {2, 1}, {1, 1}, {1, 2}, {0, 2}},
+			optimal: 5,
+		},
+		"star": {
+			Polygon: PolarPolygon{
+				{3, angle.Rot(0.0 / 8.0)},
+				{1, angle.Rot(1.0 / 8.0)},
+				{3, angle.Rot(2.0 / 8.0)},
+				{1, angle.Rot(3.0 / 8.0)},
+				{3, angle.Rot(4.0 / 8.0)},
+				{1, angle.Rot(5.0 / 8.0)},
+				{3, angle.Rot(6.0 / 8.0)},
+				{1, angle.Rot(7.0 / 8.0)},
+			}.Polygon(d2.Pt{}),
+			optimal: 3,
+		},
+	}
+
+	for n, tc := range tt {
+		t.Run(n, func(t *testing.T) {
+			for _, p := range []Polygon{tc.Polygon, tc.Reverse()} {
+				hm := p.ConvexDecomposition()
+				assertDecomposition(t, p, hm)
+				opt := p.OptimalConvexDecomposition()
+				assertDecomposition(t, p, opt)
+				assert.Len(t, opt, tc.optimal)
+				assert.True(t, len(hm) >= len(opt))
+				assert.True(t, len(hm) <= 4*len(opt))
+			}
+		})
+	}
+}
+
+func TestConvexDecompositionRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(31415))
+	for i := 0; i < 20; i++ {
+		ln := 5 + r.Intn(15)
+		pp := make(PolarPolygon, ln)
+		for j := range pp {
+			pp[j].M = 1 + 4*r.Float64()
+			pp[j].A = angle.Rot((float64(j) + 0.8*r.Float64()) / float64(ln))
+		}
+		p := pp.Polygon(d2.Pt{})
+		hm := p.ConvexDecomposition()
+		assertDecomposition(t, p, hm)
+		opt := p.OptimalConvexDecomposition()
+		assertDecomposition(t, p, opt)
+		assert.True(t, len(hm) >= len(opt), "%v", p)
+	}
+}
+
+func TestConcavePieces(t *testing.T) {
+	p := Polygon{{1, 0}, {2, 0}, {2, 1}, {3, 1}, {3, 2}, {2, 2}, {2, 3}, {1, 3}, {1, 2}, {0, 2}, {0, 1}, {1, 1}}
+	c := NewConcavePolygon(p).WithPieces(p.OptimalConvexDecomposition())
+	assert.Len(t, c.Pieces(), 3)
+
+	assert.True(t, c.PiecesContain(d2.Pt{1.5, 1.5}))
+	assert.True(t, c.PiecesContain(d2.Pt{0.5, 1.5}))
+	assert.True(t, c.PiecesContain(d2.Pt{1, 1}))
+	assert.False(t, c.PiecesContain(d2.Pt{0.5, 0.5}))
+	assert.False(t, c.PiecesContain(d2.Pt{4, 1.5}))
+
+	// Contains uses the polygon, not the pieces, so it still matches
+	// Polygon.Contains when the polygon intersects itself
+	bow := Polygon{{0, 0}, {2, 2}, {2, 0}, {0, 2}}
+	bc := NewConcavePolygon(bow)
+	for _, pt := range []d2.Pt{{0.2, 1}, {1.8, 1}, {1, 0.2}, {1, 1.8}} {
+		assert.Equal(t, bow.Contains(pt), bc.Contains(pt), pt)
+	}
+
+	// each unit square of the plus should get about the same number of points
+	r := rand.New(rand.NewSource(27182))
+	counts := make(map[[2]int]int)
+	const n = 5000
+	for i := 0; i < n; i++ {
+		pt := c.AreaPt2(r.Float64(), r.Float64())
+		assert.True(t, c.PiecesContain(pt))
+		counts[[2]int{int(pt.X), int(pt.Y)}]++
+	}
+	assert.Len(t, counts, 5)
+	for _, ct := range counts {
+		assert.InDelta(t, n/5, ct, n/20)
+	}
+}
@@ -0,0 +1,346 @@
+package polygon
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// FillRule selects which areas enclosed by a set of rings are filled based on
+// the winding number of the rings around a point.
+type FillRule byte
+
+const (
+	// Positive fills areas with a positive winding number. Areas enclosed by
+	// rings that proceed clockwise are not filled.
+	Positive FillRule = iota
+	// NonZero fills areas with a non-zero winding number.
+	NonZero
+	// EvenOdd fills areas with an odd winding number.
+	EvenOdd
+)
+
+func (r FillRule) filled(w int) bool {
+	switch r {
+	case NonZero:
+		return w != 0
+	case EvenOdd:
+		return w%2 != 0
+	}
+	return w > 0
+}
+
+// fillOffset is the distance from the midpoint of an edge to the points used
+// to find the winding number on either side of it.
+const fillOffset = 1e-7
+
+// Fill returns the area filled by the rings under the fill rule as rings that
+// do not intersect. The rings may intersect themselves and each other. Outer
+// boundaries proceed counter-clockwise and holes proceed clockwise. This takes
+// O((n+k) log n) time for n sides with k crossings.
+func Fill(rule FillRule, rings ...Polygon) []Polygon {
+	var rs []Polygon
+	for _, r := range rings {
+		if len(r) >= 3 {
+			rs = append(rs, r)
+		}
+	}
+	if len(rs) == 0 {
+		return nil
+	}
+
+	var edges []edge
+	seen := make(map[edge]bool)
+	split := splitRings(rs)
+	var all []edge
+	for _, es := range split {
+		all = append(all, es...)
+	}
+	pts := make([]d2.Pt, 0, 2*len(all))
+	for _, e := range all {
+		d := e.to.Subtract(e.from)
+		n := d2.V{-d.Y, d.X}.Multiply(fillOffset / d.Mag())
+		mid := line.New(e.from, e.to).Pt1(0.5)
+		pts = append(pts, mid.Add(n), mid.Add(n.Multiply(-1)))
+	}
+	ws := windingsAt(all, pts)
+	for i, e := range all {
+		l, r := rule.filled(ws[2*i]), rule.filled(ws[2*i+1])
+		if l == r {
+			continue
+		}
+		if r {
+			e = edge{e.to, e.from}
+		}
+		if !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	return linkRings(edges)
+}
+
+// splitRings breaks the sides of every ring at the points where they meet a
+// side of any ring, including their own. Points within BooleanTolerance of each
+// other are merged so the edges link up exactly. The crossings and the vertices
+// that lie on a side are both found with line.SweepPts.
+func splitRings(rings []Polygon) [][]edge {
+	pts := newPointSet()
+	type side struct {
+		ring, idx int
+	}
+	var sides []side
+	var ls []line.Line
+	var vs []d2.Pt
+	splits := make([][][]split, len(rings))
+	for ri, r := range rings {
+		splits[ri] = make([][]split, len(r))
+		for i, s := range r.Sides() {
+			sides = append(sides, side{ri, i})
+			ls = append(ls, s)
+		}
+		for _, pt := range r {
+			pts.canonical(pt)
+			vs = append(vs, pt)
+		}
+	}
+	cs, contacts := line.SweepPts(ls, vs, float64(BooleanTolerance))
+	for _, c := range cs {
+		a, b := sides[c.A], sides[c.B]
+		pt := pts.canonical(ls[c.A].Pt1(c.TA))
+		splits[a.ring][a.idx] = append(splits[a.ring][a.idx], split{c.TA, pt})
+		splits[b.ring][b.idx] = append(splits[b.ring][b.idx], split{c.TB, pt})
+	}
+	for _, c := range contacts {
+		a := sides[c.Seg]
+		if t, ok := onSide(ls[c.Seg], vs[c.Pt]); ok {
+			splits[a.ring][a.idx] = append(splits[a.ring][a.idx], split{t, vs[c.Pt]})
+		}
+	}
+
+	out := make([][]edge, len(rings))
+	for ri, r := range rings {
+		out[ri] = pts.edges(splitEdges(r, splits[ri]))
+	}
+	return out
+}
+
+// windingsAt finds the winding number of the edges around each point. The edges
+// may only meet at their end points, as they do after splitRings. A horizontal
+// line is swept from the bottom up holding the edges that cross it in order of
+// x so the winding number of a point is the sum over the edges to its right.
+// This takes O((n+m) log n) time for n edges and m points.
+func windingsAt(edges []edge, pts []d2.Pt) []int {
+	type event struct {
+		y    float64
+		kind byte // remove, insert or query, the order they are handled in at a y
+		idx  int
+	}
+	const (
+		remove byte = iota
+		insert
+		query
+	)
+	var evs []event
+	for i, e := range edges {
+		if e.from.Y != e.to.Y {
+			lo, hi := math.Min(e.from.Y, e.to.Y), math.Max(e.from.Y, e.to.Y)
+			evs = append(evs, event{lo, insert, i}, event{hi, remove, i})
+		}
+	}
+	for i, pt := range pts {
+		evs = append(evs, event{pt.Y, query, i})
+	}
+	sort.Slice(evs, func(i, j int) bool {
+		if evs[i].y != evs[j].y {
+			return evs[i].y < evs[j].y
+		}
+		return evs[i].kind < evs[j].kind
+	})
+
+	ws := &windingSweep{
+		edges: edges,
+		nodes: make([]*windingNode, len(edges)),
+		rand:  1,
+	}
+	out := make([]int, len(pts))
+	for _, ev := range evs {
+		ws.y = ev.y
+		switch ev.kind {
+		case remove:
+			ws.remove(ws.nodes[ev.idx])
+		case insert:
+			ws.insert(ev.idx)
+		case query:
+			out[ev.idx] = ws.right(pts[ev.idx].X)
+		}
+	}
+	return out
+}
+
+// windingNode is a node in a treap holding the edges crossing the sweep line of
+// windingsAt. sum is the total of w over the subtree.
+type windingNode struct {
+	e                   int
+	pri                 uint32
+	w, sum              int
+	left, right, parent *windingNode
+}
+
+func (n *windingNode) total() int {
+	if n == nil {
+		return 0
+	}
+	return n.sum
+}
+
+func (n *windingNode) update() {
+	n.sum = n.w + n.left.total() + n.right.total()
+}
+
+type windingSweep struct {
+	edges []edge
+	nodes []*windingNode
+	root  *windingNode
+	y     float64
+	rand  uint32
+}
+
+// x of the edge on the sweep line.
+func (s *windingSweep) x(e edge) float64 {
+	switch s.y {
+	case e.from.Y:
+		return e.from.X
+	case e.to.Y:
+		return e.to.X
+	}
+	return e.from.X + (s.y-e.from.Y)*(e.to.X-e.from.X)/(e.to.Y-e.from.Y)
+}
+
+// less orders the edges on the sweep line just above it. Edges are only
+// inserted at their lower end point so edges that meet there are ordered by
+// where they go.
+func (s *windingSweep) less(a, b int) bool {
+	ea, eb := s.edges[a], s.edges[b]
+	xa, xb := s.x(ea), s.x(eb)
+	if xa != xb {
+		return xa < xb
+	}
+	da, db := ea.to.Subtract(ea.from), eb.to.Subtract(eb.from)
+	ia, ib := da.X/da.Y, db.X/db.Y
+	if ia != ib {
+		return ia < ib
+	}
+	return a < b
+}
+
+func (s *windingSweep) insert(e int) {
+	n := &windingNode{e: e, pri: s.priority(), w: 1}
+	if s.edges[e].from.Y > s.edges[e].to.Y {
+		n.w = -1
+	}
+	n.sum = n.w
+	s.nodes[e] = n
+	if s.root == nil {
+		s.root = n
+		return
+	}
+	cur := s.root
+	for {
+		if s.less(e, cur.e) {
+			if cur.left == nil {
+				cur.left = n
+				break
+			}
+			cur = cur.left
+		} else {
+			if cur.right == nil {
+				cur.right = n
+				break
+			}
+			cur = cur.right
+		}
+	}
+	n.parent = cur
+	for p := cur; p != nil; p = p.parent {
+		p.update()
+	}
+	for n.parent != nil && n.parent.pri < n.pri {
+		s.rotateUp(n)
+	}
+}
+
+func (s *windingSweep) remove(n *windingNode) {
+	for n.left != nil || n.right != nil {
+		c := n.left
+		if c == nil || (n.right != nil && n.right.pri > c.pri) {
+			c = n.right
+		}
+		s.rotateUp(c)
+	}
+	p := n.parent
+	s.replaceChild(p, n, nil)
+	for ; p != nil; p = p.parent {
+		p.update()
+	}
+}
+
+// right is the sum of the windings of the edges to the right of x.
+func (s *windingSweep) right(x float64) int {
+	var sum int
+	for n := s.root; n != nil; {
+		if s.x(s.edges[n.e]) > x {
+			sum += n.w + n.right.total()
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return sum
+}
+
+func (s *windingSweep) priority() uint32 {
+	// xorshift
+	s.rand ^= s.rand << 13
+	s.rand ^= s.rand >> 17
+	s.rand ^= s.rand << 5
+	return s.rand
+}
+
+// rotateUp moves n above its parent.
+func (s *windingSweep) rotateUp(n *windingNode) {
+	p := n.parent
+	if p.left == n {
+		p.left = n.right
+		if n.right != nil {
+			n.right.parent = p
+		}
+		n.right = p
+	} else {
+		p.right = n.left
+		if n.left != nil {
+			n.left.parent = p
+		}
+		n.left = p
+	}
+	s.replaceChild(p.parent, p, n)
+	p.parent = n
+	p.update()
+	n.update()
+}
+
+func (s *windingSweep) replaceChild(parent, old, n *windingNode) {
+	if n != nil {
+		n.parent = parent
+	}
+	switch {
+	case parent == nil:
+		s.root = n
+	case parent.left == old:
+		parent.left = n
+	default:
+		parent.right = n
+	}
+}
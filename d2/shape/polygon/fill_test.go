@@ -0,0 +1,150 @@
+package polygon
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFill(t *testing.T) {
+	bowtie := Polygon{{0, 0}, {2, 2}, {2, 0}, {0, 2}}
+	ps := Fill(Positive, bowtie)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 1.0, ps[0].SignedArea())
+	}
+
+	a, b := square(0, 0, 2), square(1, 1, 2)
+	ps = Fill(Positive, a, b)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 7.0, ps[0].SignedArea())
+	}
+
+	ps = Fill(Positive, square(0, 0, 4), square(1, 1, 2).Reverse())
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, 12.0, sum(signedAreas(ps)))
+	}
+	assert.Len(t, Fill(Positive, square(0, 0, 1).Reverse()), 0)
+
+	// the same ring twice is not duplicated
+	ps = Fill(Positive, a, a)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 4.0, ps[0].SignedArea())
+	}
+}
+
+func TestFillRules(t *testing.T) {
+	bowtie := Polygon{{0, 0}, {2, 2}, {2, 0}, {0, 2}}
+	ps := Fill(NonZero, bowtie)
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, []float64{1, 1}, signedAreas(ps))
+	}
+
+	a, b := square(0, 0, 2), square(1, 1, 2)
+	ps = Fill(NonZero, a, b)
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 7.0, ps[0].SignedArea())
+	}
+	ps = Fill(EvenOdd, a, b)
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, []float64{3, 3}, signedAreas(ps))
+	}
+
+	ps = Fill(NonZero, square(0, 0, 4), square(1, 1, 2).Reverse())
+	if assert.Len(t, ps, 2) {
+		geomtest.Equal(t, 12.0, sum(signedAreas(ps)))
+	}
+	// a clockwise ring is filled under NonZero but not Positive
+	ps = Fill(NonZero, square(0, 0, 1).Reverse())
+	if assert.Len(t, ps, 1) {
+		geomtest.Equal(t, 1.0, ps[0].SignedArea())
+	}
+}
+
+func TestWindingsAt(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	rings := make([]Polygon, 3)
+	for i := range rings {
+		rings[i] = make(Polygon, 30)
+		for j := range rings[i] {
+			// integer points so that many edges share y values
+			rings[i][j] = d2.Pt{float64(r.Intn(20)), float64(r.Intn(20))}
+		}
+	}
+	var edges []edge
+	for _, es := range splitRings(rings) {
+		edges = append(edges, es...)
+	}
+	pts := make([]d2.Pt, 1000)
+	for i := range pts {
+		pts[i] = d2.Pt{r.Float64() * 20, float64(r.Intn(40)) / 2}
+	}
+	got := windingsAt(edges, pts)
+	var checked int
+	for i, pt := range pts {
+		var expected int
+		var onAny bool
+		for _, r := range rings {
+			w, on := r.winding(pt)
+			expected += w
+			onAny = onAny || on
+		}
+		if !onAny {
+			checked++
+			assert.Equal(t, expected, got[i], pt)
+		}
+	}
+	assert.True(t, checked > 900)
+}
+
+// zigzag is a ring with n teeth along the bottom and a notch reaching down
+// between each pair of teeth that crosses them.
+func zigzag(n int) Polygon {
+	p := make(Polygon, 0, 4*n+3)
+	for i := 0; i < n; i++ {
+		x := float64(i)
+		p = append(p, d2.Pt{x, 0}, d2.Pt{x + 0.5, 1})
+	}
+	p = append(p, d2.Pt{float64(n), 0}, d2.Pt{float64(n), 2})
+	for i := n - 1; i >= 0; i-- {
+		x := float64(i)
+		p = append(p, d2.Pt{x + 0.75, 2}, d2.Pt{x + 0.5, 0.5})
+	}
+	return append(p, d2.Pt{0, 2})
+}
+
+// bar is a rectangle across the top of zigzag(n).
+func bar(n int) Polygon {
+	return Polygon{{-1, 1.5}, {float64(n + 1), 1.5}, {float64(n + 1), 3}, {-1, 3}}
+}
+
+func TestFillLarge(t *testing.T) {
+	// each tooth adds the same pieces and area
+	fill := func(n int) []Polygon { return Fill(NonZero, zigzag(n)) }
+	union := func(n int) []Polygon { return zigzag(n).Union(bar(n)) }
+	for _, fn := range []func(int) []Polygon{fill, union} {
+		one, two := fn(1), fn(2)
+		a1, a2 := sum(signedAreas(one)), sum(signedAreas(two))
+		got := fn(2000)
+		assert.Len(t, got, len(one)+1999*(len(two)-len(one)))
+		geomtest.EqualInDelta(t, a1+1999*(a2-a1), sum(signedAreas(got)), 1e-6)
+	}
+}
+
+func BenchmarkFill(b *testing.B) {
+	p := zigzag(2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Fill(NonZero, p)
+	}
+}
+
+func BenchmarkUnion(b *testing.B) {
+	p, q := zigzag(2000), bar(2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Union(q)
+	}
+}
@@ -36,16 +36,30 @@ func GrahamScan(pts ...d2.Pt) []d2.Pt {
 	}
 
 	type ptRef struct {
-		idx int
-		ang angle.Rad
+		idx  int
+		ang  angle.Rad
+		dist float64
 	}
-	order := make([]ptRef, ln-1)
+	order := make([]ptRef, 0, ln-1)
 	for i := 0; i < ln-1; i++ {
 		idx := (least + i + 1) % ln
-		order[i].idx = idx
-		order[i].ang = pts[idx].Subtract(pts[least]).Angle()
+		v := pts[idx].Subtract(pts[least])
+		if GrahamTolerance.Zero(v.X) && GrahamTolerance.Zero(v.Y) {
+			continue
+		}
+		order = append(order, ptRef{
+			idx:  idx,
+			ang:  v.Angle(),
+			dist: v.Mag2(),
+		})
+	}
+	if len(order) == 0 {
+		return []d2.Pt{pts[least]}
 	}
 	sort.Slice(order, func(i, j int) bool {
+		if order[i].ang == order[j].ang {
+			return order[i].dist < order[j].dist
+		}
 		return order[i].ang < order[j].ang
 	})
 
@@ -67,6 +81,10 @@ func GrahamScan(pts ...d2.Pt) []d2.Pt {
 				sln++
 				break
 			} else if c < float64(GrahamTolerance) {
+				// collinear, keep pt if it is further along the same side
+				if side := stack[sln-1].Subtract(stack[sln-2]); d2.Dot(side) > 0 && d2.Mag2() > side.Mag2() {
+					stack[sln-1] = pt
+				}
 				break
 			}
 			sln--
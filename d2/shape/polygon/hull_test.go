@@ -15,6 +15,9 @@ func TestGrahamScan(t *testing.T) {
 		"convexQuad":  {{0, 0}, {1, 0}, {1, 1}, {0, 1}},
 		"concaveQuad": {{0, 0}, {1, 1}, {0, 0.5}, {-1, 1}},
 		//"3OnLineQuad": {{0, 0}, {1, 1}, {0, 0.5}, {-1, -1}},
+		"collinear":      {{0, 0}, {4, 0}, {4, 4}, {0, 4}, {5, 0}, {6, 0}, {6, 1}, {5, 1}},
+		"duplicates":     {{0, 0}, {0, 2}, {0, 2}, {2, 2}, {2, 0}, {0, 0}},
+		"collinearSides": {{2, 0}, {0, 0}, {1, 0}, {2, 1}, {2, 2}, {0, 2}, {0, 1}},
 		"regression": {{264.1697, 34.9189}, {320.8487, 34.9189},
 			{434.2065, 47.9243}, {448.3762, 60.9297}, {462.5460, 86.9405},
 			{462.5460, 99.9459}, {448.3762, 256.0108}, {434.2065, 282.0216},
@@ -43,6 +43,27 @@ func (p Polygon) Pt1(t0 float64) d2.Pt {
 	return line.New(p[idx], p[(idx+1)%ln]).Pt1(t0)
 }
 
+// Nearest returns the parametric value of the point on the perimeter closest to
+// pt and the distance to that point.
+func (p Polygon) Nearest(pt d2.Pt) (float64, float64) {
+	ln := len(p)
+	bestT, bestD := 0.0, math.Inf(1)
+	for i := range p {
+		t, d := p.Side(i).Nearest(pt)
+		if d < bestD {
+			bestT, bestD = (float64(i)+t)/float64(ln), d
+		}
+	}
+	return bestT, bestD
+}
+
+// Closest fulfills shape.Closest and returns the point on the perimeter closest
+// to pt.
+func (p Polygon) Closest(pt d2.Pt) d2.Pt {
+	t, _ := p.Nearest(pt)
+	return p.Pt1(t)
+}
+
 // String lists the points as a string.
 func (p Polygon) String() string {
 	pts := make([]string, len(p))
@@ -88,25 +109,31 @@ func (p Polygon) Centroid() d2.Pt {
 // Contains returns true of the point f is inside of the polygon. This is done
 // with the winding number algorithm and runs in O(n).
 func (p Polygon) Contains(pt d2.Pt) bool {
+	w, on := p.winding(pt)
+	return on || w != 0
+}
+
+// winding returns the winding number of the polygon around pt. If pt lies on
+// the perimeter, on is true and the winding number is not computed.
+func (p Polygon) winding(pt d2.Pt) (w int, on bool) {
 	// https://en.wikipedia.org/wiki/Point_in_polygon#Winding_number_algorithm
-	windings := 0
 	prev := p[len(p)-1]
 	for _, cur := range p {
 		c := line.New(prev, cur).Cross(pt)
 		if c == 0 &&
 			((pt.X >= prev.X && pt.X <= cur.X) || (pt.X <= prev.X && pt.X >= cur.X)) &&
 			((pt.Y >= prev.Y && pt.Y <= cur.Y) || (pt.Y <= prev.Y && pt.Y >= cur.Y)) {
-			return true
+			return 0, true
 		} else if prev.Y <= pt.Y {
 			if c > 0 && cur.Y > pt.Y {
-				windings++
+				w++
 			}
 		} else if c < 0 && cur.Y <= pt.Y {
-			windings--
+			w--
 		}
 		prev = cur
 	}
-	return windings != 0
+	return w, false
 }
 
 // Perimeter returngs the total length of the perimeter
@@ -163,7 +190,8 @@ const small = 1e-5
 // FindTriangles returns the index sets of the polygon broken up into triangles.
 // Given a unit square it would return [[0,1,2], [0,2,3]] which means that
 // the square can be broken up in to 2 triangles formed by the points at those
-// indexes.
+// indexes. The polygon should not intersect itself, use Split to break up a
+// polygon that does.
 func (p Polygon) FindTriangles() [][3]uint32 {
 	// This is the ear clipping, there are better algorithms
 	out := make([][3]uint32, 0, len(p)-2)
@@ -275,6 +303,11 @@ func (cs Collisions) P2(p2 Polygon) []d2.Pt {
 	return out
 }
 
+// SweepThreshold is the total number of sides at which PolygonCollisions,
+// SelfIntersections and NonIntersecting switch from checking every pair of
+// sides to using line.Sweep.
+var SweepThreshold = 64
+
 // PolygonIntersections finds the intersection points between two polygons.
 func (p Polygon) PolygonCollisions(p2 Polygon) Collisions {
 	ln := len(p)
@@ -285,6 +318,23 @@ func (p Polygon) PolygonCollisions(p2 Polygon) Collisions {
 	sides := p.Sides()
 	sides2 := p2.Sides()
 	var out Collisions
+	if ln+ln2 >= SweepThreshold {
+		for _, c := range line.Sweep(append(sides, sides2...)) {
+			if c.A >= ln || c.B < ln {
+				continue
+			}
+			t, t2, ok := line.DefaultRange.Check(c.TA, c.TB, true)
+			if ok {
+				out = append(out, Collision{
+					PIdx:  c.A,
+					P2Idx: c.B - ln,
+					PT:    t,
+					P2T:   t2,
+				})
+			}
+		}
+		return out
+	}
 	for idx, s := range sides {
 		for idx2, s2 := range sides2 {
 			t, t2, ok := line.DefaultRange.Check(s.Intersection(s2))
@@ -322,9 +372,12 @@ func (p Polygon) Side(n int) line.Line {
 	return line.New(p[n], p[(n+1)%ln])
 }
 
-// NonIntersecting returns false if any two sides intersect. This requires
-// O(N^2) time to check.
+// NonIntersecting returns false if any two sides intersect. For polygons with
+// fewer than SweepThreshold sides, this requires O(N^2) time to check.
 func (p Polygon) NonIntersecting() bool {
+	if len(p) >= SweepThreshold {
+		return len(p.SelfIntersections()) == 0
+	}
 	side := p.Sides()
 	// Each side needs to be check against each non-adjacent side with a greater
 	// index.
@@ -362,3 +415,29 @@ func (p Polygon) BoundingBox() (min, max d2.Pt) {
 func (p Polygon) ConvexHull() []d2.Pt {
 	return ConvexHull(p...)
 }
+
+// Pt1Segment fulfills d2.Segmenter returning the range [t0, t1] of the
+// perimeter as line.Segments.
+func (p Polygon) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return line.Segments(append(p[:len(p):len(p)], p[0])).Segment(t0, t1)
+}
+
+// Pt1Reverse fulfills d2.Reverser. Unlike Reverse, the first point is kept in
+// place so that the perimeter is traced from the same start.
+func (p Polygon) Pt1Reverse() d2.Pt1 {
+	out := make(Polygon, len(p))
+	for i := range p {
+		out[i] = p[(len(p)-i)%len(p)]
+	}
+	return out
+}
+
+// T applies the transform to each point.
+func (p Polygon) T(t *d2.T) Polygon {
+	return t.Slice(p)
+}
+
+// Pt1T fulfills d2.Transformer.
+func (p Polygon) Pt1T(t *d2.T) d2.Pt1 {
+	return p.T(t)
+}
@@ -427,3 +427,31 @@ func TestNew(t *testing.T) {
 
 	geomtest.Equal(t, p, New(pts))
 }
+
+func TestNearest(t *testing.T) {
+	p := Polygon{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	tt, d := p.Nearest(d2.Pt{5, 1})
+	geomtest.Equal(t, 0.3125, tt)
+	geomtest.Equal(t, 1.0, d)
+	geomtest.Equal(t, d2.Pt{4, 1}, p.Closest(d2.Pt{5, 1}))
+
+	// inside points are measured to the perimeter
+	tt, d = p.Nearest(d2.Pt{1, 2})
+	geomtest.Equal(t, 0.875, tt)
+	geomtest.Equal(t, 1.0, d)
+	geomtest.Equal(t, d2.Pt{0, 2}, p.Closest(d2.Pt{1, 2}))
+}
+
+func TestPt1SegmentReverseT(t *testing.T) {
+	p := Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	s := p.Pt1Segment(0.125, 0.625)
+	assert.Equal(t, line.Segments{{1, 0}, {2, 0}, {2, 2}, {1, 2}}, s)
+
+	r := p.Pt1Reverse()
+	tr := d2.Rotate(angle.Deg(30)).T()
+	pt := p.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, p.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(p.Pt1(i)), pt.Pt1(i))
+	}
+}
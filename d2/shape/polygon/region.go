@@ -0,0 +1,353 @@
+package polygon
+
+import (
+	"math"
+	"sort"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+)
+
+// Region is a Polygon with holes. The Outer ring proceeds counter-clockwise and
+// the Holes proceed clockwise. The Holes should be inside Outer and should not
+// overlap each other.
+type Region struct {
+	Outer Polygon
+	Holes []Polygon
+}
+
+// NewRegion creates a Region, reversing the rings as needed so that the outer
+// ring is counter-clockwise and the holes are clockwise.
+func NewRegion(outer Polygon, holes ...Polygon) Region {
+	if outer.SignedArea() < 0 {
+		outer = outer.Reverse()
+	}
+	hs := make([]Polygon, len(holes))
+	for i, h := range holes {
+		if h.SignedArea() > 0 {
+			h = h.Reverse()
+		}
+		hs[i] = h
+	}
+	return Region{
+		Outer: outer,
+		Holes: hs,
+	}
+}
+
+// Rings returns the outer ring followed by the holes.
+func (r Region) Rings() []Polygon {
+	return append([]Polygon{r.Outer}, r.Holes...)
+}
+
+// Contains fulfills shape.Container. Points inside a hole are not contained.
+func (r Region) Contains(pt d2.Pt) bool {
+	if !r.Outer.Contains(pt) {
+		return false
+	}
+	for _, h := range r.Holes {
+		if h.Contains(pt) && !onPerimeter(h, pt) {
+			return false
+		}
+	}
+	return true
+}
+
+func onPerimeter(p Polygon, pt d2.Pt) bool {
+	_, d := p.Nearest(pt)
+	return BooleanTolerance.Zero(d)
+}
+
+// LineIntersections fulfills line.Intersector returning the intersections with
+// the outer ring and the holes.
+func (r Region) LineIntersections(l line.Line, buf []float64) []float64 {
+	return ringIntersections(r.Rings(), l, buf)
+}
+
+func ringIntersections(rings []Polygon, l line.Line, buf []float64) []float64 {
+	max := len(buf)
+	buf = buf[:0]
+	for _, ring := range rings {
+		buf = append(buf, ring.LineIntersections(l, nil)...)
+		if max > 0 && len(buf) >= max {
+			return buf[:max]
+		}
+	}
+	return buf
+}
+
+// ConvexHull fulfills shape.ConvexHuller.
+func (r Region) ConvexHull() []d2.Pt {
+	return r.Outer.ConvexHull()
+}
+
+// SignedArea is the area of the outer ring less the area of the holes. It is
+// always positive.
+func (r Region) SignedArea() float64 {
+	a := r.Outer.Area()
+	for _, h := range r.Holes {
+		a -= h.Area()
+	}
+	return a
+}
+
+// Area of the Region.
+func (r Region) Area() float64 {
+	return r.SignedArea()
+}
+
+// Centroid of the Region.
+func (r Region) Centroid() d2.Pt {
+	a := r.Outer.Area()
+	c := r.Outer.Centroid().V().Multiply(a)
+	for _, h := range r.Holes {
+		ha := h.Area()
+		a -= ha
+		c = c.Subtract(h.Centroid().V().Multiply(ha))
+	}
+	return c.Multiply(1 / a).Pt()
+}
+
+// Perimeter is the total length of all the rings.
+func (r Region) Perimeter() float64 {
+	p := r.Outer.Perimeter()
+	for _, h := range r.Holes {
+		p += h.Perimeter()
+	}
+	return p
+}
+
+// BoundingBox fulfills shape.BoundingBoxer.
+func (r Region) BoundingBox() (min, max d2.Pt) {
+	return r.Outer.BoundingBox()
+}
+
+// Polygon joins the holes to the outer ring with bridges, producing a single
+// Polygon that traces the same Region. Each bridge is traced once in each
+// direction so the Polygon touches itself along the bridges. Holes are never
+// left out; if a hole is not inside Outer there may be no bridge that stays
+// inside the Region and the closest vertices are joined.
+func (r Region) Polygon() Polygon {
+	// https://www.geometrictools.com/Documentation/TriangulationByEarClipping.pdf
+	r = NewRegion(r.Outer, r.Holes...)
+	holes := make([]Polygon, len(r.Holes))
+	copy(holes, r.Holes)
+	sort.Slice(holes, func(i, j int) bool {
+		return rightmost(holes[i]) > rightmost(holes[j])
+	})
+
+	out := append(Polygon(nil), r.Outer...)
+	for hi, h := range holes {
+		mi := 0
+		for i, pt := range h {
+			if pt.X > h[mi].X {
+				mi = i
+			}
+		}
+		m := h[mi]
+		vi := bridge(out, holes[hi+1:], m)
+		if vi < 0 {
+			vi, mi = visible(out, h, holes[hi+1:])
+		}
+		merged := make(Polygon, 0, len(out)+len(h)+2)
+		merged = append(merged, out[:vi+1]...)
+		merged = append(merged, h[mi:]...)
+		merged = append(merged, h[:mi+1]...)
+		merged = append(merged, out[vi:]...)
+		out = merged
+	}
+	return out
+}
+
+func rightmost(p Polygon) float64 {
+	x := math.Inf(-1)
+	for _, pt := range p {
+		if pt.X > x {
+			x = pt.X
+		}
+	}
+	return x
+}
+
+// bridge finds the index of the closest point in p that can be connected to m
+// without crossing p or any of the remaining holes.
+func bridge(p Polygon, holes []Polygon, m d2.Pt) int {
+	best, bestD := -1, math.Inf(1)
+	for i, pt := range p {
+		if pt.X < m.X {
+			continue
+		}
+		d := pt.Distance(m)
+		if d >= bestD {
+			continue
+		}
+		l := line.New(m, pt)
+		if !crosses(p, l) && !blocked(holes, l) {
+			best, bestD = i, d
+		}
+	}
+	return best
+}
+
+// visible is used when bridge fails. It finds the closest pair of a vertex of p
+// and a vertex of h that can be connected inside of p without crossing p, h or
+// any of the remaining holes. If there is no such pair, the closest pair is
+// returned.
+func visible(p, h Polygon, holes []Polygon) (pi, hi int) {
+	pi, hi = -1, -1
+	closestP, closestH := 0, 0
+	bestD, closestD := math.Inf(1), math.Inf(1)
+	for j, m := range h {
+		for i, pt := range p {
+			d := pt.Distance(m)
+			if d < closestD {
+				closestP, closestH, closestD = i, j, d
+			}
+			if d >= bestD {
+				continue
+			}
+			l := line.New(m, pt)
+			mid := l.Pt1(0.5)
+			if p.Contains(mid) && !h.Contains(mid) && !crosses(p, l) && !crosses(h, l) && !blocked(holes, l) {
+				pi, hi, bestD = i, j, d
+			}
+		}
+	}
+	if pi < 0 {
+		return closestP, closestH
+	}
+	return pi, hi
+}
+
+func blocked(holes []Polygon, l line.Line) bool {
+	for _, h := range holes {
+		if crosses(h, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// crosses returns true if l crosses a side of p anywhere other than the end
+// points of l.
+func crosses(p Polygon, l line.Line) bool {
+	ln := len(p)
+	for i, pt := range p {
+		side := line.New(pt, p[(i+1)%ln])
+		t0, t1, ok := l.Intersection(side)
+		if ok && t0 > small && t0 < 1-small && t1 >= 0 && t1 <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// FindTriangles returns the index sets of the triangles that make up the
+// Region. The indexes are relative to the Polygon returned by Region.Polygon.
+func (r Region) FindTriangles() [][3]uint32 {
+	return r.Polygon().FindTriangles()
+}
+
+// MultiPolygon is a collection of Regions that do not overlap.
+type MultiPolygon []Region
+
+// NewMultiPolygon groups rings into Regions. Counter-clockwise rings are outer
+// rings and clockwise rings are holes, which is the form returned by the
+// Polygon boolean operations. Each hole is assigned to the smallest outer ring
+// that contains it.
+func NewMultiPolygon(rings []Polygon) MultiPolygon {
+	var out MultiPolygon
+	var holes []Polygon
+	for _, r := range rings {
+		if r.SignedArea() >= 0 {
+			out = append(out, Region{Outer: r})
+		} else {
+			holes = append(holes, r)
+		}
+	}
+	for _, h := range holes {
+		best, bestA := -1, 0.0
+		for i, r := range out {
+			if a := r.Outer.Area(); r.Outer.Contains(h.Centroid()) && (best == -1 || a < bestA) {
+				best, bestA = i, a
+			}
+		}
+		if best >= 0 {
+			out[best].Holes = append(out[best].Holes, h)
+		}
+	}
+	return out
+}
+
+// Contains fulfills shape.Container.
+func (m MultiPolygon) Contains(pt d2.Pt) bool {
+	for _, r := range m {
+		if r.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// LineIntersections fulfills line.Intersector returning the intersections with
+// all the rings of all the Regions.
+func (m MultiPolygon) LineIntersections(l line.Line, buf []float64) []float64 {
+	var rings []Polygon
+	for _, r := range m {
+		rings = append(rings, r.Rings()...)
+	}
+	return ringIntersections(rings, l, buf)
+}
+
+// ConvexHull fulfills shape.ConvexHuller.
+func (m MultiPolygon) ConvexHull() []d2.Pt {
+	var pts []d2.Pt
+	for _, r := range m {
+		pts = append(pts, r.Outer...)
+	}
+	return ConvexHull(pts...)
+}
+
+// SignedArea is the total area of the Regions.
+func (m MultiPolygon) SignedArea() float64 {
+	var a float64
+	for _, r := range m {
+		a += r.SignedArea()
+	}
+	return a
+}
+
+// Area is the total area of the Regions.
+func (m MultiPolygon) Area() float64 {
+	return m.SignedArea()
+}
+
+// Centroid of all the Regions.
+func (m MultiPolygon) Centroid() d2.Pt {
+	var a float64
+	var c d2.V
+	for _, r := range m {
+		ra := r.Area()
+		a += ra
+		c = c.Add(r.Centroid().V().Multiply(ra))
+	}
+	return c.Multiply(1 / a).Pt()
+}
+
+// Perimeter is the total length of all the rings of all the Regions.
+func (m MultiPolygon) Perimeter() float64 {
+	var p float64
+	for _, r := range m {
+		p += r.Perimeter()
+	}
+	return p
+}
+
+// BoundingBox fulfills shape.BoundingBoxer.
+func (m MultiPolygon) BoundingBox() (min, max d2.Pt) {
+	var pts []d2.Pt
+	for _, r := range m {
+		pts = append(pts, r.Outer...)
+	}
+	return d2.MinMax(pts...)
+}
@@ -0,0 +1,117 @@
+package polygon
+
+import (
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func letterO() Region {
+	return NewRegion(square(0, 0, 4), square(1, 1, 2))
+}
+
+func TestRegion(t *testing.T) {
+	r := letterO()
+	assert.True(t, r.Holes[0].SignedArea() < 0)
+
+	geomtest.Equal(t, 12.0, r.Area())
+	geomtest.Equal(t, 24.0, r.Perimeter())
+	geomtest.Equal(t, d2.Pt{2, 2}, r.Centroid())
+	min, max := r.BoundingBox()
+	geomtest.Equal(t, d2.Pt{0, 0}, min)
+	geomtest.Equal(t, d2.Pt{4, 4}, max)
+
+	assert.True(t, r.Contains(d2.Pt{0.5, 2}))
+	assert.True(t, r.Contains(d2.Pt{1, 2}))
+	assert.False(t, r.Contains(d2.Pt{2, 2}))
+	assert.False(t, r.Contains(d2.Pt{5, 2}))
+
+	l := line.New(d2.Pt{-1, 2}, d2.Pt{5, 2})
+	is := r.LineIntersections(l, nil)
+	assert.Len(t, is, 4)
+	assert.Len(t, r.LineIntersections(l, []float64{0, 0}), 2)
+
+	// The centroid shifts away from an off center hole
+	r2 := NewRegion(square(0, 0, 4), square(2.5, 1.5, 1))
+	c := r2.Centroid()
+	assert.True(t, c.X < 2)
+	geomtest.Equal(t, 2.0, c.Y)
+}
+
+func TestRegionTriangles(t *testing.T) {
+	tt := map[string]Region{
+		"O":   letterO(),
+		"two": NewRegion(square(0, 0, 6), square(1, 1, 1), square(3, 3, 2)),
+		"concave": NewRegion(
+			Polygon{{0, 0}, {6, 0}, {6, 6}, {3, 3}, {0, 6}},
+			square(1, 1, 1), square(4, 1, 1),
+		),
+	}
+	for n, r := range tt {
+		t.Run(n, func(t *testing.T) {
+			p := r.Polygon()
+			geomtest.Equal(t, r.Area(), p.SignedArea())
+
+			ts := GetTriangles(r.FindTriangles(), p)
+			var a float64
+			for _, tri := range ts {
+				a += tri.Area()
+				assert.True(t, r.Contains(tri.Centroid()))
+			}
+			geomtest.Equal(t, r.Area(), a)
+
+			c := NewConcaveRegion(r)
+			geomtest.Equal(t, r.Perimeter(), c.Perimeter())
+			geomtest.Equal(t, r.Area(), c.Area())
+			for i := 0.05; i < 1; i += 0.1 {
+				for j := 0.05; j < 1; j += 0.1 {
+					assert.True(t, r.Contains(c.Pt2(i, j)))
+				}
+			}
+		})
+	}
+}
+
+func TestRegionBridgeFallback(t *testing.T) {
+	// the right point of the hole is outside of the outer ring so there is no
+	// point to its right to bridge to
+	outer := square(0, 0, 10)
+	hole := Polygon{{8, 6}, {11, 5}, {8, 4}}
+	assert.Equal(t, -1, bridge(outer, nil, d2.Pt{11, 5}))
+
+	p := NewRegion(outer, hole).Polygon()
+	assert.Len(t, p, len(outer)+len(hole)+2)
+	geomtest.Equal(t, 97.0, p.SignedArea())
+	for _, pt := range hole {
+		assert.Contains(t, p, pt)
+	}
+}
+
+func TestMultiPolygon(t *testing.T) {
+	// A square with a hole plus a separate square.
+	rings := append(square(0, 0, 4).Difference(square(1, 1, 2)), square(5, 0, 1))
+	m := NewMultiPolygon(rings)
+	if assert.Len(t, m, 2) {
+		assert.Len(t, m[0].Holes, 1)
+		assert.Len(t, m[1].Holes, 0)
+	}
+
+	geomtest.Equal(t, 13.0, m.Area())
+	geomtest.Equal(t, 28.0, m.Perimeter())
+	geomtest.Equal(t, d2.Pt{(24 + 5.5) / 13, 2.0*12/13 + 0.5/13}, m.Centroid())
+	min, max := m.BoundingBox()
+	geomtest.Equal(t, d2.Pt{0, 0}, min)
+	geomtest.Equal(t, d2.Pt{6, 4}, max)
+
+	assert.True(t, m.Contains(d2.Pt{0.5, 0.5}))
+	assert.True(t, m.Contains(d2.Pt{5.5, 0.5}))
+	assert.False(t, m.Contains(d2.Pt{2, 2}))
+	assert.False(t, m.Contains(d2.Pt{4.5, 0.5}))
+
+	l := line.New(d2.Pt{-1, 0.5}, d2.Pt{7, 0.5})
+	assert.Len(t, m.LineIntersections(l, nil), 4)
+	geomtest.Equal(t, AssertConvexHuller{{0, 0}, {6, 0}, {6, 1}, {4, 4}, {0, 4}}, m)
+}
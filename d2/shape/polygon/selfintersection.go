@@ -0,0 +1,53 @@
+package polygon
+
+import "github.com/adamcolton/geom/d2/curve/line"
+
+// SelfIntersections returns every point where two sides of the polygon cross.
+// Both indexes of each Collision refer to sides of p, with PIdx less than
+// P2Idx, so Collision.P and Collision.P2 should both be called with p. Sides
+// that only touch at their end points are not included, which matches
+// NonIntersecting. Polygons with at least SweepThreshold sides use line.Sweep,
+// smaller polygons compare every pair of sides.
+func (p Polygon) SelfIntersections() Collisions {
+	if len(p) < 4 {
+		return nil
+	}
+	side := p.Sides()
+	var out Collisions
+	if len(p) >= SweepThreshold {
+		for _, c := range line.Sweep(side) {
+			if c.B > c.A+1 && c.TA > 0 && c.TA < 1 && c.TB > 0 && c.TB < 1 {
+				out = append(out, Collision{
+					PIdx:  c.A,
+					P2Idx: c.B,
+					PT:    c.TA,
+					P2T:   c.TB,
+				})
+			}
+		}
+		return out
+	}
+	for i, si := range side[:len(side)-2] {
+		for j := i + 2; j < len(side); j++ {
+			t0, t1, ok := si.Intersection(side[j])
+			if ok && t0 > 0 && t0 < 1 && t1 > 0 && t1 < 1 {
+				out = append(out, Collision{
+					PIdx:  i,
+					P2Idx: j,
+					PT:    t0,
+					P2T:   t1,
+				})
+			}
+		}
+	}
+	return out
+}
+
+// Split breaks a polygon that intersects itself into polygons that do not,
+// keeping the areas filled under the rule. Outer boundaries proceed
+// counter-clockwise and holes proceed clockwise. For example, under NonZero a
+// bow-tie becomes two triangles and under EvenOdd a pentagram becomes five
+// triangles.
+func (p Polygon) Split(rule FillRule) []Polygon {
+	return Fill(rule, p)
+}
@@ -0,0 +1,103 @@
+package polygon
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func pentagram() Polygon {
+	p := make(Polygon, 5)
+	for i := range p {
+		s, c := math.Sincos(math.Pi/2 + 4*math.Pi*float64(i)/5)
+		p[i] = d2.Pt{c, s}
+	}
+	return p
+}
+
+func TestSelfIntersections(t *testing.T) {
+	assert.Len(t, square(0, 0, 1).SelfIntersections(), 0)
+
+	bowtie := Polygon{{0, 0}, {2, 2}, {2, 0}, {0, 2}}
+	cs := bowtie.SelfIntersections()
+	if assert.Len(t, cs, 1) {
+		assert.Equal(t, 0, cs[0].PIdx)
+		assert.Equal(t, 2, cs[0].P2Idx)
+		geomtest.Equal(t, 0.5, cs[0].PT)
+		geomtest.Equal(t, 0.5, cs[0].P2T)
+		geomtest.Equal(t, d2.Pt{1, 1}, cs[0].P(bowtie))
+		geomtest.Equal(t, d2.Pt{1, 1}, cs[0].P2(bowtie))
+	}
+
+	star := pentagram()
+	assert.Len(t, star.SelfIntersections(), 5)
+	assert.False(t, star.NonIntersecting())
+}
+
+func TestSplit(t *testing.T) {
+	bowtie := Polygon{{0, 0}, {2, 2}, {2, 0}, {0, 2}}
+	for _, r := range []FillRule{NonZero, EvenOdd} {
+		ps := bowtie.Split(r)
+		if assert.Len(t, ps, 2) {
+			for _, p := range ps {
+				geomtest.Equal(t, 1.0, p.SignedArea())
+				assert.True(t, p.NonIntersecting())
+				assert.Len(t, p.FindTriangles(), 1)
+			}
+		}
+	}
+
+	star := pentagram()
+	ps := star.Split(NonZero)
+	if assert.Len(t, ps, 1) {
+		assert.Len(t, ps[0], 10)
+		assert.True(t, ps[0].NonIntersecting())
+	}
+
+	ps = star.Split(EvenOdd)
+	if assert.Len(t, ps, 5) {
+		for _, p := range ps {
+			assert.Len(t, p, 3)
+		}
+		// the center of the star is not filled
+		assert.InDelta(t, ps[0].SignedArea()*5, sum(signedAreas(ps)), 1e-9)
+		for _, p := range ps {
+			assert.False(t, p.Contains(d2.Pt{0, 0}))
+		}
+	}
+}
+
+func TestSweepThreshold(t *testing.T) {
+	defer func(th int) { SweepThreshold = th }(SweepThreshold)
+
+	r := rand.New(rand.NewSource(1))
+	noisy := func(n int, c d2.Pt) Polygon {
+		p := make(Polygon, n)
+		for i := range p {
+			s, cs := math.Sincos(6 * math.Pi * float64(i) / float64(n))
+			rd := 5 + 3*r.Float64()
+			p[i] = d2.Pt{c.X + rd*cs, c.Y + rd*s}
+		}
+		return p
+	}
+	a, b := noisy(120, d2.Pt{0, 0}), noisy(90, d2.Pt{3, 1})
+
+	SweepThreshold = 1 << 30
+	collisions := a.PolygonCollisions(b)
+	self := a.SelfIntersections()
+	union := a.Union(b)
+	assert.NotEmpty(t, collisions)
+	assert.NotEmpty(t, self)
+	assert.False(t, a.NonIntersecting())
+
+	SweepThreshold = 4
+	assert.Equal(t, collisions, a.PolygonCollisions(b))
+	assert.Equal(t, self, a.SelfIntersections())
+	assert.False(t, a.NonIntersecting())
+	assert.Equal(t, union, a.Union(b))
+	assert.True(t, square(0, 0, 1).NonIntersecting())
+}
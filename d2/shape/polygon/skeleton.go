@@ -0,0 +1,298 @@
+package polygon
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+)
+
+// Skeleton is the straight skeleton of a polygon; the paths traced by the
+// vertices as the sides move inward at the same speed. Pts holds the vertices
+// of the polygon followed by the nodes where the vertices meet. Height is the
+// distance the sides had moved when each point was reached, it is 0 for the
+// vertices of the polygon. Using Height as the third dimension produces a roof
+// over the polygon. Edges connect the indexes of Pts.
+type Skeleton struct {
+	Pts    []d2.Pt
+	Height []float64
+	Edges  [][2]uint32
+	ln     int
+}
+
+// Interior returns the Edges that connect two nodes. For a long, thin polygon
+// this is the centerline.
+func (s Skeleton) Interior() [][2]uint32 {
+	var out [][2]uint32
+	for _, e := range s.Edges {
+		if int(e[0]) >= s.ln && int(e[1]) >= s.ln {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// StraightSkeleton of a simple polygon. The polygon may proceed in either
+// direction. Where more than two vertices meet at the same point, a single node
+// is used.
+func (p Polygon) StraightSkeleton() Skeleton {
+	// https://www.dma.fi.upm.es/personal/mabellanas/tfcs/skeleton/html/documentacion/Straight%20Skeletons%20Implementation.pdf
+	ln := len(p)
+	s := &skeleton{
+		Skeleton: Skeleton{
+			Pts:    append([]d2.Pt(nil), p...),
+			Height: make([]float64, ln),
+			ln:     ln,
+		},
+	}
+	min, max := p.BoundingBox()
+	s.eps = 1e-9 * (1 + max.Subtract(min).Mag())
+
+	idx := make([]int, 0, ln)
+	for i := range p {
+		if p[i] != p[(i+1)%ln] {
+			idx = append(idx, i)
+		}
+	}
+	if p.SignedArea() < 0 {
+		for i, j := 0, len(idx)-1; i < j; i, j = i+1, j-1 {
+			idx[i], idx[j] = idx[j], idx[i]
+		}
+	}
+	if len(idx) < 3 {
+		return s.Skeleton
+	}
+
+	for i, pi := range idx {
+		from, to := p[pi], p[idx[(i+1)%len(idx)]]
+		d := to.Subtract(from)
+		d = d.Multiply(1 / d.Mag())
+		s.sides = append(s.sides, skeletonSide{
+			p0: from,
+			d:  d,
+			n:  d2.V{-d.Y, d.X},
+		})
+	}
+	vs := make([]*skeletonVertex, len(idx))
+	for i, pi := range idx {
+		vs[i] = s.vertex(p[pi], 0, uint32(pi), (i+len(idx)-1)%len(idx), i)
+	}
+	for i, v := range vs {
+		v.next = vs[(i+1)%len(vs)]
+		v.next.prev = v
+	}
+	s.active = vs
+
+	for i := 0; i < 4*len(idx)+4 && len(s.active) > 0; i++ {
+		e, ok := s.nextEvent()
+		if !ok {
+			break
+		}
+		if e.u == nil {
+			s.edgeEvent(e)
+		} else {
+			s.splitEvent(e)
+		}
+	}
+	return s.Skeleton
+}
+
+type skeletonSide struct {
+	p0   d2.Pt
+	d, n d2.V // unit direction and inward normal
+}
+
+// skeletonVertex is a vertex of the wavefront. It moves with velocity v from
+// pt, where it was at time t0.
+type skeletonVertex struct {
+	pt         d2.Pt
+	t0         float64
+	v          d2.V
+	node       uint32
+	l, r       int // index of the sides before and after the vertex
+	reflex     bool
+	stuck      bool // the sides are anti-parallel, so v is undefined
+	dead       bool
+	prev, next *skeletonVertex
+}
+
+func (v *skeletonVertex) at(t float64) d2.Pt {
+	return v.pt.Add(v.v.Multiply(t - v.t0))
+}
+
+type skeletonEvent struct {
+	t float64
+	x d2.Pt
+	// for an edge event, v and v.next meet. For a split event, v meets the
+	// side from u to u.next.
+	v, u *skeletonVertex
+}
+
+type skeleton struct {
+	Skeleton
+	sides  []skeletonSide
+	active []*skeletonVertex
+	eps    float64
+	now    float64
+}
+
+func (s *skeleton) vertex(pt d2.Pt, t float64, node uint32, l, r int) *skeletonVertex {
+	v := &skeletonVertex{
+		pt:   pt,
+		t0:   t,
+		node: node,
+		l:    l,
+		r:    r,
+	}
+	nl, nr := s.sides[l].n, s.sides[r].n
+	if den := 1 + nl.Dot(nr); den > 1e-9 {
+		v.v = nl.Add(nr).Multiply(1 / den)
+	} else {
+		v.stuck = true
+	}
+	v.reflex = s.sides[l].d.Cross(s.sides[r].d) < 0
+	return v
+}
+
+// nextEvent finds the earliest event by checking every pair of neighboring
+// vertices and every reflex vertex against every side.
+func (s *skeleton) nextEvent() (skeletonEvent, bool) {
+	best := skeletonEvent{t: math.Inf(1)}
+	for _, a := range s.active {
+		if t, x, ok := s.edgeTime(a, a.next); ok && t < best.t {
+			best = skeletonEvent{t: t, x: x, v: a}
+		}
+	}
+	for _, v := range s.active {
+		if !v.reflex || v.stuck {
+			continue
+		}
+		for u := v.next; u.next != v; u = u.next {
+			if t, x, ok := s.splitTime(v, u); ok && t < best.t-s.eps {
+				best = skeletonEvent{t: t, x: x, v: v, u: u}
+			}
+		}
+	}
+	return best, !math.IsInf(best.t, 1)
+}
+
+func dot(v d2.V, pt d2.Pt) float64 {
+	return v.X*pt.X + v.Y*pt.Y
+}
+
+// edgeTime finds when a and b meet as the side between them shrinks to nothing.
+func (s *skeleton) edgeTime(a, b *skeletonVertex) (float64, d2.Pt, bool) {
+	if a.stuck || b.stuck {
+		return 0, d2.Pt{}, false
+	}
+	d := s.sides[a.r].d
+	den := d.Dot(a.v) - d.Dot(b.v)
+	if den <= 1e-12 {
+		return 0, d2.Pt{}, false
+	}
+	t := (dot(d, b.pt) - b.t0*d.Dot(b.v) - dot(d, a.pt) + a.t0*d.Dot(a.v)) / den
+	if t < s.now-s.eps {
+		return 0, d2.Pt{}, false
+	}
+	pa, pb := a.at(t), b.at(t)
+	return t, d2.Pt{(pa.X + pb.X) / 2, (pa.Y + pb.Y) / 2}, true
+}
+
+// splitTime finds when the reflex vertex v hits the side from u to u.next.
+func (s *skeleton) splitTime(v, u *skeletonVertex) (float64, d2.Pt, bool) {
+	side := s.sides[u.r]
+	nv := side.n.Dot(v.v)
+	den := 1 - nv
+	if den <= 1e-12 {
+		return 0, d2.Pt{}, false
+	}
+	t := (side.n.Dot(v.pt.Subtract(side.p0)) - nv*v.t0) / den
+	if t < s.now-s.eps {
+		return 0, d2.Pt{}, false
+	}
+	x := v.at(t)
+	s0, s1, sx := dot(side.d, u.at(t)), dot(side.d, u.next.at(t)), dot(side.d, x)
+	if s0 >= s1 || sx < s0-s.eps || sx > s1+s.eps {
+		return 0, d2.Pt{}, false
+	}
+	return t, x, true
+}
+
+// node returns the index of a node at pt, reusing an existing node if one is
+// close enough.
+func (s *skeleton) node(pt d2.Pt, t float64) uint32 {
+	for i := s.ln; i < len(s.Pts); i++ {
+		if s.Pts[i].Distance(pt) < 1e3*s.eps {
+			return uint32(i)
+		}
+	}
+	s.Pts = append(s.Pts, pt)
+	s.Height = append(s.Height, t)
+	return uint32(len(s.Pts) - 1)
+}
+
+func (s *skeleton) edge(a, b uint32) {
+	if a == b {
+		return
+	}
+	for _, e := range s.Edges {
+		if (e[0] == a && e[1] == b) || (e[0] == b && e[1] == a) {
+			return
+		}
+	}
+	s.Edges = append(s.Edges, [2]uint32{a, b})
+}
+
+func (s *skeleton) edgeEvent(e skeletonEvent) {
+	s.now = e.t
+	a, b := e.v, e.v.next
+	n := s.node(e.x, e.t)
+	s.edge(a.node, n)
+	s.edge(b.node, n)
+	c := s.vertex(e.x, e.t, n, a.l, b.r)
+	c.prev, c.next = a.prev, b.next
+	a.prev.next, b.next.prev = c, c
+	a.dead, b.dead = true, true
+	s.replace(c)
+}
+
+func (s *skeleton) splitEvent(e skeletonEvent) {
+	s.now = e.t
+	v, u := e.v, e.u
+	w := u.next
+	n := s.node(e.x, e.t)
+	s.edge(v.node, n)
+
+	v1 := s.vertex(e.x, e.t, n, v.l, u.r)
+	v2 := s.vertex(e.x, e.t, n, u.r, v.r)
+	v1.prev, v1.next = v.prev, w
+	v.prev.next, w.prev = v1, v1
+	v2.prev, v2.next = u, v.next
+	u.next, v.next.prev = v2, v2
+	v.dead = true
+	s.replace(v1, v2)
+}
+
+// replace removes the dead vertices from active and adds the new vertices.
+// A wavefront reduced to two vertices is closed by connecting them.
+func (s *skeleton) replace(vs ...*skeletonVertex) {
+	for _, v := range vs {
+		if v.next.next == v {
+			s.edge(v.node, v.next.node)
+			v.dead, v.next.dead = true, true
+		} else if v.next == v {
+			v.dead = true
+		}
+	}
+	active := s.active[:0]
+	for _, v := range s.active {
+		if !v.dead {
+			active = append(active, v)
+		}
+	}
+	for _, v := range vs {
+		if !v.dead {
+			active = append(active, v)
+		}
+	}
+	s.active = active
+}
@@ -0,0 +1,92 @@
+package polygon
+
+import (
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/geomtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertTree checks that the skeleton is a tree connecting every point.
+func assertTree(t *testing.T, s Skeleton) {
+	assert.Len(t, s.Edges, len(s.Pts)-1)
+	parent := make([]int, len(s.Pts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, e := range s.Edges {
+		parent[find(int(e[0]))] = find(int(e[1]))
+	}
+	for i := range s.Pts {
+		assert.Equal(t, find(0), find(i))
+	}
+}
+
+func TestStraightSkeleton(t *testing.T) {
+	s := square(0, 0, 2).StraightSkeleton()
+	if assert.Len(t, s.Pts, 5) {
+		geomtest.Equal(t, d2.Pt{1, 1}, s.Pts[4])
+		geomtest.Equal(t, 1.0, s.Height[4])
+	}
+	assertTree(t, s)
+	assert.Len(t, s.Interior(), 0)
+
+	rect := Polygon{{0, 0}, {0, 2}, {4, 2}, {4, 0}}
+	s = rect.StraightSkeleton()
+	if assert.Len(t, s.Pts, 6) {
+		geomtest.Equal(t, []float64{0, 0, 0, 0, 1, 1}, s.Height)
+		in := s.Interior()
+		if assert.Len(t, in, 1) {
+			a, b := s.Pts[in[0][0]], s.Pts[in[0][1]]
+			if a.X > b.X {
+				a, b = b, a
+			}
+			geomtest.Equal(t, d2.Pt{1, 1}, a)
+			geomtest.Equal(t, d2.Pt{3, 1}, b)
+		}
+	}
+	assertTree(t, s)
+}
+
+func TestStraightSkeletonConvex(t *testing.T) {
+	p := Polygon{{0, 0}, {5, 0}, {7, 3}, {4, 6}, {-1, 4}}
+	s := p.StraightSkeleton()
+	assert.Len(t, s.Pts, 2*len(p)-2)
+	assertTree(t, s)
+	for i := len(p); i < len(s.Pts); i++ {
+		_, d := p.Nearest(s.Pts[i])
+		geomtest.Equal(t, d, s.Height[i])
+	}
+}
+
+func TestStraightSkeletonConcave(t *testing.T) {
+	l := Polygon{{0, 0}, {4, 0}, {4, 2}, {1, 2}, {1, 3.5}, {0, 3.5}}
+	s := l.StraightSkeleton()
+	assert.Len(t, s.Pts, 2*len(l)-2)
+	assertTree(t, s)
+	for i := len(l); i < len(s.Pts); i++ {
+		assert.True(t, l.Contains(s.Pts[i]))
+		assert.True(t, s.Height[i] > 0)
+	}
+
+	// the reflex vertex at the bottom of the notch splits the wavefront when
+	// it reaches the bottom side
+	notch := Polygon{{0, 0}, {10, 0}, {10, 4}, {5.2, 4}, {5, 1}, {4.8, 4}, {0, 4}}
+	s = notch.StraightSkeleton()
+	assert.Len(t, s.Pts, 2*len(notch)-2)
+	assertTree(t, s)
+	split := s.Pts[len(notch)]
+	geomtest.Equal(t, 5.0, split.X)
+	geomtest.Equal(t, split.Y, s.Height[len(notch)])
+	for i := len(notch); i < len(s.Pts); i++ {
+		assert.True(t, notch.Contains(s.Pts[i]))
+	}
+}
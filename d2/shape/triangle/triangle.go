@@ -146,3 +146,25 @@ func (t *Triangle) CircumCenter() d2.Pt {
 func (t *Triangle) ConvexHull() []d2.Pt {
 	return t[:]
 }
+
+// Pt1Segment fulfills d2.Segmenter returning the range [t0, t1] of the
+// perimeter as line.Segments.
+func (t *Triangle) Pt1Segment(t0, t1 float64) d2.Pt1 {
+	return t.Pt1c0().(line.Segments).Segment(t0, t1)
+}
+
+// Pt1Reverse fulfills d2.Reverser returning a Triangle that traces the perimeter
+// in the opposite direction from the same start.
+func (t *Triangle) Pt1Reverse() d2.Pt1 {
+	return &Triangle{t[0], t[2], t[1]}
+}
+
+// T applies the transform to each point.
+func (t *Triangle) T(tfrm *d2.T) *Triangle {
+	return &Triangle{tfrm.Pt(t[0]), tfrm.Pt(t[1]), tfrm.Pt(t[2])}
+}
+
+// Pt1T fulfills d2.Transformer.
+func (t *Triangle) Pt1T(tfrm *d2.T) d2.Pt1 {
+	return t.T(tfrm)
+}
@@ -184,3 +184,17 @@ func TestConvexHull(t *testing.T) {
 	a := polygon.AssertConvexHuller(append((*tri)[:], d2.Pt{0.25, 0.25}))
 	geomtest.Equal(t, a, tri)
 }
+
+func TestSegmentReverseT(t *testing.T) {
+	tri := &triangle.Triangle{{0, 0}, {3, 0}, {0, 3}}
+	s := d2.Segment(tri, 0, 0.5)
+	assert.Equal(t, line.Segments{{0, 0}, {3, 0}, {1.5, 1.5}}, s)
+
+	r := d2.Reverse(tri)
+	tr := d2.Rotate(angle.Deg(30)).T()
+	tt := tri.T(tr)
+	for i := 0.0; i <= 1.0; i += 0.125 {
+		geomtest.Equal(t, tri.Pt1(1-i), r.Pt1(i))
+		geomtest.Equal(t, tr.Pt(tri.Pt1(i)), tt.Pt1(i))
+	}
+}
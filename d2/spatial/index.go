@@ -0,0 +1,166 @@
+// Package spatial indexes shapes by their bounding boxes so that queries do not
+// need to check every shape. RTree is a good default, QuadTree is simpler and
+// works well when the shapes are small and evenly spread. Both are safe for any
+// number of concurrent readers and a writer blocks the readers.
+package spatial
+
+import (
+	"math"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+)
+
+// ID identifies a shape in an Index. Shapes do not need to be comparable, so
+// they are deleted by ID.
+type ID uint32
+
+// Index of shapes by their bounding boxes. The queries append the matching
+// shapes to buf, in no particular order, and return it.
+type Index interface {
+	Insert(s shape.BoundingBoxer) ID
+	Delete(id ID) bool
+	Get(id ID) (shape.BoundingBoxer, bool)
+	Len() int
+	// Pt finds the shapes that contain pt. If a shape fulfills
+	// shape.Container, it is checked, otherwise the bounding box is used.
+	Pt(pt d2.Pt, buf []shape.BoundingBoxer) []shape.BoundingBoxer
+	// Box finds the shapes with a bounding box that overlaps b.
+	Box(b box.Box, buf []shape.BoundingBoxer) []shape.BoundingBoxer
+	// Segment finds the shapes that intersect l with a parametric value
+	// between 0 and 1. If a shape fulfills line.Intersector, it is checked,
+	// otherwise the bounding box is used.
+	Segment(l line.Line, buf []shape.BoundingBoxer) []shape.BoundingBoxer
+	// Ray is the same as Segment for any parametric value of 0 or more.
+	Ray(l line.Line, buf []shape.BoundingBoxer) []shape.BoundingBoxer
+}
+
+type entry struct {
+	id ID
+	b  box.Box
+	s  shape.BoundingBoxer
+}
+
+func newEntry(id ID, s shape.BoundingBoxer) entry {
+	min, max := s.BoundingBox()
+	return entry{
+		id: id,
+		b:  box.Box{min, max},
+		s:  s,
+	}
+}
+
+func overlaps(a, b box.Box) bool {
+	return a[0].X <= b[1].X && b[0].X <= a[1].X &&
+		a[0].Y <= b[1].Y && b[0].Y <= a[1].Y
+}
+
+// within returns true if a is inside of b.
+func within(a, b box.Box) bool {
+	return a[0].X >= b[0].X && a[1].X <= b[1].X &&
+		a[0].Y >= b[0].Y && a[1].Y <= b[1].Y
+}
+
+func union(a, b box.Box) box.Box {
+	return box.Box{d2.Min(a[0], b[0]), d2.Max(a[1], b[1])}
+}
+
+func area(b box.Box) float64 {
+	v := b.V()
+	return v.X * v.Y
+}
+
+func hasPt(b box.Box, pt d2.Pt) bool {
+	return pt.X >= b[0].X && pt.X <= b[1].X && pt.Y >= b[0].Y && pt.Y <= b[1].Y
+}
+
+// query holds one of the kinds of query.
+type query struct {
+	kind byte
+	pt   d2.Pt
+	b    box.Box
+	l    line.Line
+	tMax float64
+}
+
+const (
+	ptQuery byte = iota
+	boxQuery
+	lineQuery
+)
+
+func ptQ(pt d2.Pt) query {
+	return query{kind: ptQuery, pt: pt}
+}
+
+func boxQ(b box.Box) query {
+	return query{kind: boxQuery, b: b}
+}
+
+func segmentQ(l line.Line) query {
+	return query{kind: lineQuery, l: l, tMax: 1}
+}
+
+func rayQ(l line.Line) query {
+	return query{kind: lineQuery, l: l, tMax: math.Inf(1)}
+}
+
+// box returns true if the query may match a shape inside b.
+func (q query) box(b box.Box) bool {
+	switch q.kind {
+	case ptQuery:
+		return hasPt(b, q.pt)
+	case boxQuery:
+		return overlaps(q.b, b)
+	}
+	return lineBox(q.l, b, q.tMax)
+}
+
+// match returns true if the query matches the entry.
+func (q query) match(e entry) bool {
+	if !q.box(e.b) {
+		return false
+	}
+	switch q.kind {
+	case ptQuery:
+		if c, ok := e.s.(shape.Container); ok {
+			return c.Contains(q.pt)
+		}
+	case lineQuery:
+		if i, ok := e.s.(line.Intersector); ok {
+			for _, t := range i.LineIntersections(q.l, nil) {
+				if t >= 0 && t <= q.tMax {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// lineBox returns true if l intersects b with a parametric value between 0 and
+// tMax.
+func lineBox(l line.Line, b box.Box, tMax float64) bool {
+	t0, t1, ok := slab(l.T0.X, l.D.X, b[0].X, b[1].X, 0, tMax)
+	if !ok {
+		return false
+	}
+	_, _, ok = slab(l.T0.Y, l.D.Y, b[0].Y, b[1].Y, t0, t1)
+	return ok
+}
+
+// slab narrows the range from t0 to t1 to where p+d*t is between min and max.
+func slab(p, d, min, max, t0, t1 float64) (float64, float64, bool) {
+	if d == 0 {
+		return t0, t1, p >= min && p <= max
+	}
+	ta, tb := (min-p)/d, (max-p)/d
+	if ta > tb {
+		ta, tb = tb, ta
+	}
+	t0, t1 = math.Max(t0, ta), math.Min(t1, tb)
+	return t0, t1, t0 <= t1
+}
@@ -0,0 +1,336 @@
+package spatial
+
+import (
+	"math"
+	"sync"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+)
+
+// QuadTreeMax is the number of shapes a node of a QuadTree holds before it is
+// split into quadrants.
+const QuadTreeMax = 8
+
+// QuadTreeDepth limits how many times a QuadTree node can be split.
+const QuadTreeDepth = 16
+
+// QuadTree fulfills Index. Each shape is held by the smallest node whose
+// quadrant contains its bounding box. The root grows to hold shapes inserted
+// outside of it. Shapes with a bounding box that is not finite cannot be placed
+// in a quadrant and are not added.
+type QuadTree struct {
+	mu    sync.RWMutex
+	root  *qNode
+	nodes map[ID]*qNode
+	next  ID
+}
+
+type qNode struct {
+	b        box.Box
+	depth    int
+	entries  []entry
+	children *[4]*qNode
+	parent   *qNode
+	// count is the number of entries in the node and below it.
+	count int
+}
+
+// NewQuadTree creates a QuadTree holding the shapes. The ID of each shape is
+// its index.
+func NewQuadTree(shapes ...shape.BoundingBoxer) *QuadTree {
+	t := &QuadTree{
+		nodes: make(map[ID]*qNode, len(shapes)),
+		next:  ID(len(shapes)),
+	}
+	es := make([]entry, 0, len(shapes))
+	var bounds box.Box
+	for i, s := range shapes {
+		e := newEntry(ID(i), s)
+		if !finite(e.b) {
+			continue
+		}
+		if len(es) == 0 {
+			bounds = e.b
+		} else {
+			bounds = union(bounds, e.b)
+		}
+		es = append(es, e)
+	}
+	if len(es) > 0 {
+		t.root = &qNode{b: square(bounds)}
+	}
+	for _, e := range es {
+		t.insert(e)
+	}
+	return t
+}
+
+// square returns the smallest square with the same min point that contains b.
+// It has a size of at least 1 so it can be split.
+func square(b box.Box) box.Box {
+	v := b.V()
+	s := v.X
+	if v.Y > s {
+		s = v.Y
+	}
+	if s <= 0 {
+		s = 1
+	}
+	return box.Box{b[0], b[0].Add(d2.V{s, s})}
+}
+
+func (n *qNode) quadrant(i int) box.Box {
+	mid := n.b.Centroid()
+	switch i {
+	case 0:
+		return box.Box{n.b[0], mid}
+	case 1:
+		return box.Box{{mid.X, n.b[0].Y}, {n.b[1].X, mid.Y}}
+	case 2:
+		return box.Box{mid, n.b[1]}
+	}
+	return box.Box{{n.b[0].X, mid.Y}, {mid.X, n.b[1].Y}}
+}
+
+// Len returns the number of shapes in the tree.
+func (t *QuadTree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.nodes)
+}
+
+// Get returns the shape with the ID.
+func (t *QuadTree) Get(id ID) (shape.BoundingBoxer, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n, ok := t.nodes[id]
+	if !ok {
+		return nil, false
+	}
+	for _, e := range n.entries {
+		if e.id == id {
+			return e.s, true
+		}
+	}
+	return nil, false
+}
+
+// Insert a shape into the tree. If the bounding box of the shape is not finite,
+// the shape is not added and Get will not find the returned ID.
+func (t *QuadTree) Insert(s shape.BoundingBoxer) ID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.nodes == nil {
+		t.nodes = make(map[ID]*qNode)
+	}
+	e := newEntry(t.next, s)
+	t.next++
+	if !finite(e.b) {
+		return e.id
+	}
+	if t.root == nil {
+		t.root = &qNode{b: square(e.b)}
+	}
+	t.insert(e)
+	return e.id
+}
+
+func (t *QuadTree) insert(e entry) {
+	for !within(e.b, t.root.b) {
+		t.grow(e.b)
+	}
+	n := t.root
+	n.count++
+	for n.children != nil {
+		next := n.child(e.b)
+		if next == nil {
+			break
+		}
+		n = next
+		n.count++
+	}
+	n.entries = append(n.entries, e)
+	t.nodes[e.id] = n
+	if n.children == nil && len(n.entries) > QuadTreeMax && n.depth < QuadTreeDepth {
+		t.split(n)
+	}
+}
+
+// child returns the quadrant that contains b, or nil if none does.
+func (n *qNode) child(b box.Box) *qNode {
+	for _, c := range n.children {
+		if within(b, c.b) {
+			return c
+		}
+	}
+	return nil
+}
+
+// grow doubles the size of the root towards b.
+func (t *QuadTree) grow(b box.Box) {
+	old := t.root
+	v := old.b.V()
+	// the old root becomes the quadrant furthest from b
+	min := old.b[0]
+	left, down := b[0].X < min.X, b[0].Y < min.Y
+	if left {
+		min.X -= v.X
+	}
+	if down {
+		min.Y -= v.Y
+	}
+	q := 0
+	switch {
+	case left && down:
+		q = 2
+	case left:
+		q = 1
+	case down:
+		q = 3
+	}
+	root := &qNode{
+		b:     box.Box{min, min.Add(v.Multiply(2))},
+		count: old.count,
+	}
+	root.children = &[4]*qNode{}
+	for i := range root.children {
+		if i == q {
+			root.children[i] = old
+		} else {
+			root.children[i] = &qNode{b: root.quadrant(i)}
+		}
+		root.children[i].parent = root
+	}
+	t.root = root
+	t.deepen(root, 0)
+}
+
+// deepen sets the depth of n and every node below it.
+func (t *QuadTree) deepen(n *qNode, depth int) {
+	n.depth = depth
+	if n.children != nil {
+		for _, c := range n.children {
+			t.deepen(c, depth+1)
+		}
+	}
+}
+
+// split n into quadrants, moving the shapes that fit in one.
+func (t *QuadTree) split(n *qNode) {
+	n.children = &[4]*qNode{}
+	for i := range n.children {
+		n.children[i] = &qNode{
+			b:      n.quadrant(i),
+			depth:  n.depth + 1,
+			parent: n,
+		}
+	}
+	es := n.entries
+	n.entries = nil
+	for _, e := range es {
+		c := n.child(e.b)
+		if c == nil {
+			c = n
+		}
+		c.entries = append(c.entries, e)
+		t.nodes[e.id] = c
+		if c != n {
+			c.count++
+		}
+	}
+	for _, c := range n.children {
+		if len(c.entries) > QuadTreeMax && c.depth < QuadTreeDepth {
+			t.split(c)
+		}
+	}
+}
+
+// Delete removes the shape with the ID from the tree. Any node left with nothing
+// below it drops its children.
+func (t *QuadTree) Delete(id ID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, ok := t.nodes[id]
+	if !ok {
+		return false
+	}
+	delete(t.nodes, id)
+	for i, e := range n.entries {
+		if e.id == id {
+			n.entries = append(n.entries[:i], n.entries[i+1:]...)
+			break
+		}
+	}
+	if len(t.nodes) == 0 {
+		t.root = nil
+		return true
+	}
+	for p := n; p != nil; p = p.parent {
+		p.count--
+		if p.children != nil && p.count == len(p.entries) {
+			p.children = nil
+		}
+	}
+	return true
+}
+
+func finite(b box.Box) bool {
+	for _, pt := range b {
+		for _, f := range [2]float64{pt.X, pt.Y} {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (t *QuadTree) query(q query, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.root == nil {
+		return buf
+	}
+	stack := []*qNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range n.entries {
+			if q.match(e) {
+				buf = append(buf, e.s)
+			}
+		}
+		if n.children == nil {
+			continue
+		}
+		for _, c := range n.children {
+			if q.box(c.b) {
+				stack = append(stack, c)
+			}
+		}
+	}
+	return buf
+}
+
+// Pt fulfills Index.
+func (t *QuadTree) Pt(pt d2.Pt, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(ptQ(pt), buf)
+}
+
+// Box fulfills Index.
+func (t *QuadTree) Box(b box.Box, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(boxQ(b), buf)
+}
+
+// Segment fulfills Index.
+func (t *QuadTree) Segment(l line.Line, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(segmentQ(l), buf)
+}
+
+// Ray fulfills Index.
+func (t *QuadTree) Ray(l line.Line, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(rayQ(l), buf)
+}
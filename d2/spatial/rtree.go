@@ -0,0 +1,404 @@
+package spatial
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+)
+
+// RTreeMax is the most children a node of an RTree can have. When a node is
+// split, each half gets at least 40% of RTreeMax children.
+const RTreeMax = 16
+
+const rTreeMin = RTreeMax * 2 / 5
+
+// RTree fulfills Index. Nodes are split with Guttman's quadratic split and
+// NewRTree bulk loads the tree by sort-tile-recursive packing.
+type RTree struct {
+	mu      sync.RWMutex
+	root    *rNode
+	entries map[ID]entry
+	next    ID
+}
+
+type rNode struct {
+	b        box.Box
+	leaf     bool
+	children []*rNode
+	entries  []entry
+}
+
+func (n *rNode) size() int {
+	if n.leaf {
+		return len(n.entries)
+	}
+	return len(n.children)
+}
+
+func (n *rNode) updateBox() {
+	first := true
+	set := func(b box.Box) {
+		if first {
+			n.b, first = b, false
+		} else {
+			n.b = union(n.b, b)
+		}
+	}
+	for _, e := range n.entries {
+		set(e.b)
+	}
+	for _, c := range n.children {
+		set(c.b)
+	}
+}
+
+// NewRTree creates an RTree holding the shapes. The ID of each shape is its
+// index.
+func NewRTree(shapes ...shape.BoundingBoxer) *RTree {
+	t := &RTree{
+		entries: make(map[ID]entry, len(shapes)),
+		next:    ID(len(shapes)),
+	}
+	es := make([]entry, len(shapes))
+	for i, s := range shapes {
+		es[i] = newEntry(ID(i), s)
+		t.entries[ID(i)] = es[i]
+	}
+	if len(es) == 0 {
+		return t
+	}
+
+	nodes := make([]*rNode, 0, (len(es)+RTreeMax-1)/RTreeMax)
+	strTile(len(es), func(i int) box.Box { return es[i].b }, func(i, j int) { es[i], es[j] = es[j], es[i] }, func(from, to int) {
+		n := &rNode{
+			leaf:    true,
+			entries: append([]entry(nil), es[from:to]...),
+		}
+		n.updateBox()
+		nodes = append(nodes, n)
+	})
+	for len(nodes) > 1 {
+		level := nodes
+		nodes = make([]*rNode, 0, (len(level)+RTreeMax-1)/RTreeMax)
+		strTile(len(level), func(i int) box.Box { return level[i].b }, func(i, j int) { level[i], level[j] = level[j], level[i] }, func(from, to int) {
+			n := &rNode{
+				children: append([]*rNode(nil), level[from:to]...),
+			}
+			n.updateBox()
+			nodes = append(nodes, n)
+		})
+	}
+	t.root = nodes[0]
+	return t
+}
+
+// strTile sorts n items into vertical slices by the center of their boxes and
+// each slice from bottom to top, then calls group for each run of up to
+// RTreeMax items.
+func strTile(n int, b func(int) box.Box, swap func(i, j int), group func(from, to int)) {
+	leaves := (n + RTreeMax - 1) / RTreeMax
+	slices := int(math.Ceil(math.Sqrt(float64(leaves))))
+	per := slices * RTreeMax
+	center := func(i int) d2.Pt {
+		bb := b(i)
+		return bb.Centroid()
+	}
+	sort.Sort(sorter{n, func(i, j int) bool { return center(i).X < center(j).X }, swap})
+	for s := 0; s < n; s += per {
+		end := s + per
+		if end > n {
+			end = n
+		}
+		sort.Sort(sorter{end - s, func(i, j int) bool { return center(s+i).Y < center(s+j).Y }, func(i, j int) { swap(s+i, s+j) }})
+		for g := s; g < end; g += RTreeMax {
+			ge := g + RTreeMax
+			if ge > end {
+				ge = end
+			}
+			group(g, ge)
+		}
+	}
+}
+
+type sorter struct {
+	n    int
+	less func(i, j int) bool
+	swap func(i, j int)
+}
+
+func (s sorter) Len() int           { return s.n }
+func (s sorter) Less(i, j int) bool { return s.less(i, j) }
+func (s sorter) Swap(i, j int)      { s.swap(i, j) }
+
+// Len returns the number of shapes in the tree.
+func (t *RTree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.entries)
+}
+
+// Get returns the shape with the ID.
+func (t *RTree) Get(id ID) (shape.BoundingBoxer, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	e, ok := t.entries[id]
+	return e.s, ok
+}
+
+// Insert a shape into the tree.
+func (t *RTree) Insert(s shape.BoundingBoxer) ID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[ID]entry)
+	}
+	e := newEntry(t.next, s)
+	t.next++
+	t.entries[e.id] = e
+	t.insert(e)
+	return e.id
+}
+
+func (t *RTree) insert(e entry) {
+	if t.root == nil {
+		t.root = &rNode{leaf: true, b: e.b}
+	}
+	if split := t.insertAt(t.root, e); split != nil {
+		root := &rNode{children: []*rNode{t.root, split}}
+		root.updateBox()
+		t.root = root
+	}
+}
+
+// insertAt adds e below n, choosing the child that grows the least. If n is
+// split, the new node is returned.
+func (t *RTree) insertAt(n *rNode, e entry) *rNode {
+	if n.size() == 0 {
+		n.b = e.b
+	} else {
+		n.b = union(n.b, e.b)
+	}
+	if n.leaf {
+		n.entries = append(n.entries, e)
+	} else {
+		var best *rNode
+		bestGrowth, bestArea := math.Inf(1), math.Inf(1)
+		for _, c := range n.children {
+			a := area(c.b)
+			g := area(union(c.b, e.b)) - a
+			if g < bestGrowth || (g == bestGrowth && a < bestArea) {
+				best, bestGrowth, bestArea = c, g, a
+			}
+		}
+		if split := t.insertAt(best, e); split != nil {
+			n.children = append(n.children, split)
+		}
+	}
+	if n.size() > RTreeMax {
+		return n.split()
+	}
+	return nil
+}
+
+// split moves some of the children of n to a new node using Guttman's
+// quadratic split.
+func (n *rNode) split() *rNode {
+	ln := n.size()
+	boxes := make([]box.Box, ln)
+	for i := range boxes {
+		if n.leaf {
+			boxes[i] = n.entries[i].b
+		} else {
+			boxes[i] = n.children[i].b
+		}
+	}
+
+	// pick the two that waste the most area as seeds
+	s0, s1, worst := 0, 1, math.Inf(-1)
+	for i := range boxes {
+		for j := i + 1; j < ln; j++ {
+			if d := area(union(boxes[i], boxes[j])) - area(boxes[i]) - area(boxes[j]); d > worst {
+				s0, s1, worst = i, j, d
+			}
+		}
+	}
+
+	group := make([]int, ln)
+	for i := range group {
+		group[i] = -1
+	}
+	group[s0], group[s1] = 0, 1
+	gb := [2]box.Box{boxes[s0], boxes[s1]}
+	count := [2]int{1, 1}
+	for left := ln - 2; left > 0; left-- {
+		// make sure both groups get the minimum
+		g := -1
+		if count[0]+left == rTreeMin {
+			g = 0
+		} else if count[1]+left == rTreeMin {
+			g = 1
+		}
+		if g != -1 {
+			for i := range group {
+				if group[i] == -1 {
+					group[i] = g
+					gb[g] = union(gb[g], boxes[i])
+				}
+			}
+			break
+		}
+		// pick the one with the strongest preference
+		pick, pg, diff := -1, 0, math.Inf(-1)
+		for i, b := range boxes {
+			if group[i] != -1 {
+				continue
+			}
+			d0 := area(union(gb[0], b)) - area(gb[0])
+			d1 := area(union(gb[1], b)) - area(gb[1])
+			if d := math.Abs(d0 - d1); d > diff {
+				pick, diff = i, d
+				if pg = 0; d1 < d0 || (d1 == d0 && count[1] < count[0]) {
+					pg = 1
+				}
+			}
+		}
+		group[pick] = pg
+		gb[pg] = union(gb[pg], boxes[pick])
+		count[pg]++
+	}
+
+	out := &rNode{leaf: n.leaf}
+	if n.leaf {
+		es := n.entries
+		n.entries = nil
+		for i, e := range es {
+			if group[i] == 0 {
+				n.entries = append(n.entries, e)
+			} else {
+				out.entries = append(out.entries, e)
+			}
+		}
+	} else {
+		cs := n.children
+		n.children = nil
+		for i, c := range cs {
+			if group[i] == 0 {
+				n.children = append(n.children, c)
+			} else {
+				out.children = append(out.children, c)
+			}
+		}
+	}
+	n.b, out.b = gb[0], gb[1]
+	return out
+}
+
+// Delete removes the shape with the ID from the tree. Nodes left with too few
+// children are removed and their shapes are inserted again.
+func (t *RTree) Delete(id ID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[id]
+	if !ok {
+		return false
+	}
+	delete(t.entries, id)
+
+	var orphans []entry
+	t.remove(t.root, e, &orphans)
+	if !t.root.leaf && len(t.root.children) == 1 {
+		t.root = t.root.children[0]
+	}
+	if t.root.size() == 0 {
+		t.root = nil
+	}
+	for _, o := range orphans {
+		t.insert(o)
+	}
+	return true
+}
+
+// remove e from below n, adding the entries of any nodes that are removed to
+// orphans.
+func (t *RTree) remove(n *rNode, e entry, orphans *[]entry) bool {
+	if !within(e.b, n.b) {
+		return false
+	}
+	if n.leaf {
+		for i, ne := range n.entries {
+			if ne.id == e.id {
+				n.entries = append(n.entries[:i], n.entries[i+1:]...)
+				n.updateBox()
+				return true
+			}
+		}
+		return false
+	}
+	for i, c := range n.children {
+		if !t.remove(c, e, orphans) {
+			continue
+		}
+		if c.size() < rTreeMin {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			c.collect(orphans)
+		}
+		n.updateBox()
+		return true
+	}
+	return false
+}
+
+func (n *rNode) collect(out *[]entry) {
+	*out = append(*out, n.entries...)
+	for _, c := range n.children {
+		c.collect(out)
+	}
+}
+
+func (t *RTree) query(q query, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.root == nil {
+		return buf
+	}
+	stack := []*rNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !q.box(n.b) {
+			continue
+		}
+		for _, e := range n.entries {
+			if q.match(e) {
+				buf = append(buf, e.s)
+			}
+		}
+		stack = append(stack, n.children...)
+	}
+	return buf
+}
+
+// Pt fulfills Index.
+func (t *RTree) Pt(pt d2.Pt, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(ptQ(pt), buf)
+}
+
+// Box fulfills Index.
+func (t *RTree) Box(b box.Box, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(boxQ(b), buf)
+}
+
+// Segment fulfills Index.
+func (t *RTree) Segment(l line.Line, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(segmentQ(l), buf)
+}
+
+// Ray fulfills Index.
+func (t *RTree) Ray(l line.Line, buf []shape.BoundingBoxer) []shape.BoundingBoxer {
+	return t.query(rayQ(l), buf)
+}
@@ -0,0 +1,260 @@
+package spatial
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/adamcolton/geom/d2"
+	"github.com/adamcolton/geom/d2/curve/line"
+	"github.com/adamcolton/geom/d2/shape"
+	"github.com/adamcolton/geom/d2/shape/box"
+	"github.com/adamcolton/geom/d2/shape/polygon"
+	"github.com/adamcolton/geom/work"
+	"github.com/stretchr/testify/assert"
+)
+
+// item wraps a triangle so results can be compared by pointer.
+type item struct {
+	idx int
+	polygon.Polygon
+}
+
+// boxOnly does not fulfill shape.Container or line.Intersector.
+type boxOnly struct {
+	idx int
+	b   box.Box
+}
+
+func (b *boxOnly) BoundingBox() (d2.Pt, d2.Pt) { return b.b[0], b.b[1] }
+
+// never is not matched by any of the queries.
+type never struct{}
+
+func (never) BoundingBox() (d2.Pt, d2.Pt)                      { return d2.Pt{1000, 1000}, d2.Pt{1001, 1001} }
+func (never) Contains(d2.Pt) bool                              { return false }
+func (never) LineIntersections(line.Line, []float64) []float64 { return nil }
+
+func idx(s shape.BoundingBoxer) int {
+	if i, ok := s.(*item); ok {
+		return i.idx
+	}
+	return s.(*boxOnly).idx
+}
+
+func randomShapes(r *rand.Rand, n int) []shape.BoundingBoxer {
+	out := make([]shape.BoundingBoxer, n)
+	for i := range out {
+		c := d2.Pt{r.Float64() * 100, r.Float64() * 100}
+		s := 0.5 + r.Float64()*3
+		if i%4 == 0 {
+			out[i] = &boxOnly{i, box.Box{c, c.Add(d2.V{s, s / 2})}}
+			continue
+		}
+		out[i] = &item{i, polygon.Polygon{
+			c,
+			c.Add(d2.V{s * r.Float64(), s * r.Float64()}),
+			c.Add(d2.V{-s * r.Float64(), s * r.Float64()}),
+		}}
+	}
+	return out
+}
+
+func ids(ss []shape.BoundingBoxer) []int {
+	out := make([]int, len(ss))
+	for i, s := range ss {
+		out[i] = idx(s)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func brute(ss []shape.BoundingBoxer, deleted map[int]bool, q query) []int {
+	out := []int{}
+	for i, s := range ss {
+		if !deleted[i] && q.match(newEntry(ID(i), s)) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func queries(r *rand.Rand) []query {
+	var out []query
+	for i := 0; i < 30; i++ {
+		pt := d2.Pt{r.Float64() * 100, r.Float64() * 100}
+		v := d2.V{r.Float64()*40 - 20, r.Float64()*40 - 20}
+		out = append(out,
+			ptQ(pt),
+			boxQ(box.Box{pt, pt.Add(d2.V{r.Float64() * 20, r.Float64() * 20})}),
+			segmentQ(line.New(pt, pt.Add(v))),
+			rayQ(line.Line{T0: pt, D: v}),
+		)
+	}
+	return out
+}
+
+func run(idx Index, q query) []shape.BoundingBoxer {
+	switch q.kind {
+	case ptQuery:
+		return idx.Pt(q.pt, nil)
+	case boxQuery:
+		return idx.Box(q.b, nil)
+	}
+	if q.tMax == 1 {
+		return idx.Segment(q.l, nil)
+	}
+	return idx.Ray(q.l, nil)
+}
+
+func TestIndex(t *testing.T) {
+	tt := map[string]func([]shape.BoundingBoxer) Index{
+		"rtree-bulk": func(ss []shape.BoundingBoxer) Index { return NewRTree(ss...) },
+		"rtree-insert": func(ss []shape.BoundingBoxer) Index {
+			idx := &RTree{}
+			for _, s := range ss {
+				idx.Insert(s)
+			}
+			return idx
+		},
+		"quadtree-bulk": func(ss []shape.BoundingBoxer) Index { return NewQuadTree(ss...) },
+		"quadtree-insert": func(ss []shape.BoundingBoxer) Index {
+			idx := &QuadTree{}
+			for _, s := range ss {
+				idx.Insert(s)
+			}
+			return idx
+		},
+	}
+
+	for n, fn := range tt {
+		t.Run(n, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1234))
+			ss := randomShapes(r, 2000)
+			idx := fn(ss)
+			assert.Equal(t, len(ss), idx.Len())
+			s, ok := idx.Get(17)
+			assert.True(t, ok)
+			assert.Equal(t, 17, s.(*item).idx)
+
+			qs := queries(r)
+			deleted := map[int]bool{}
+			check := func() {
+				found := 0
+				for _, q := range qs {
+					expected := brute(ss, deleted, q)
+					found += len(expected)
+					assert.Equal(t, expected, ids(run(idx, q)))
+				}
+				assert.True(t, found > 50)
+			}
+			check()
+
+			for i := 0; i < 1500; i++ {
+				d := r.Intn(len(ss))
+				assert.Equal(t, !deleted[d], idx.Delete(ID(d)))
+				deleted[d] = true
+			}
+			assert.Equal(t, len(ss)-len(deleted), idx.Len())
+			check()
+
+			for i := range ss {
+				idx.Delete(ID(i))
+			}
+			assert.Equal(t, 0, idx.Len())
+			assert.Len(t, idx.Pt(d2.Pt{50, 50}, nil), 0)
+			id := idx.Insert(ss[3])
+			assert.Equal(t, []int{3}, ids(idx.Box(box.Box{{-1000, -1000}, {1000, 1000}}, nil)))
+			assert.True(t, idx.Delete(id))
+		})
+	}
+}
+
+func TestQuadTreeGrow(t *testing.T) {
+	qt := &QuadTree{}
+	ss := []shape.BoundingBoxer{
+		&boxOnly{0, box.Box{{0, 0}, {1, 1}}},
+		&boxOnly{1, box.Box{{-10, 5}, {-9, 6}}},
+		&boxOnly{2, box.Box{{20, -30}, {21, -29}}},
+		&boxOnly{3, box.Box{{-50, -50}, {50, 50}}},
+	}
+	for _, s := range ss {
+		qt.Insert(s)
+	}
+	assert.Equal(t, []int{1, 3}, ids(qt.Pt(d2.Pt{-9.5, 5.5}, nil)))
+	assert.Equal(t, []int{2, 3}, ids(qt.Segment(line.New(d2.Pt{19, -29.5}, d2.Pt{22, -29.5}), nil)))
+	assert.Equal(t, []int{0, 3}, ids(qt.Ray(line.Line{T0: d2.Pt{-2, 0.5}, D: d2.V{1, 0}}, nil)))
+}
+
+func (n *qNode) size() int {
+	out := 1
+	if n.children != nil {
+		for _, c := range n.children {
+			out += c.size()
+		}
+	}
+	return out
+}
+
+func TestQuadTreeChurn(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	ss := randomShapes(r, 1000)
+	qt := NewQuadTree(ss...)
+	assert.True(t, qt.root.size() > 4*QuadTreeDepth+1)
+	keys := make([]ID, len(ss))
+	for i := range keys {
+		keys[i] = ID(i)
+	}
+	for round := 0; round < 5; round++ {
+		for _, id := range keys[1:] {
+			assert.True(t, qt.Delete(id))
+		}
+		// only the path to the remaining shape and the siblings along it are
+		// left
+		assert.True(t, qt.root.size() <= 4*QuadTreeDepth+1)
+		for i, s := range ss[1:] {
+			keys[i+1] = qt.Insert(s)
+		}
+	}
+	for _, q := range queries(r) {
+		assert.Equal(t, brute(ss, nil, q), ids(run(qt, q)))
+	}
+}
+
+func TestQuadTreeNotFinite(t *testing.T) {
+	nan := &boxOnly{-1, box.Box{{math.NaN(), 0}, {1, 1}}}
+	inf := &boxOnly{-1, box.Box{{0, 0}, {math.Inf(1), 1}}}
+	qt := NewQuadTree(nan, &boxOnly{1, box.Box{{0, 0}, {1, 1}}}, inf)
+	assert.Equal(t, 1, qt.Len())
+	_, ok := qt.Get(0)
+	assert.False(t, ok)
+
+	id := qt.Insert(nan)
+	_, ok = qt.Get(id)
+	assert.False(t, ok)
+	qt.Insert(inf)
+	assert.Equal(t, 1, qt.Len())
+	assert.Equal(t, []int{1}, ids(qt.Pt(d2.Pt{0.5, 0.5}, nil)))
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	r := rand.New(rand.NewSource(99))
+	ss := randomShapes(r, 1000)
+	qs := queries(r)
+	for _, idx := range []Index{NewRTree(ss...), NewQuadTree(ss...)} {
+		var bad int32
+		work.RunRange(200, func(i, _ int) {
+			q := qs[i%len(qs)]
+			if len(ids(run(idx, q))) != len(brute(ss, nil, q)) {
+				atomic.AddInt32(&bad, 1)
+			}
+			if i%50 == 0 {
+				idx.Delete(idx.Insert(never{}))
+				idx.Insert(never{})
+			}
+		})
+		assert.Equal(t, int32(0), bad)
+	}
+}
@@ -56,6 +56,15 @@ func (t *T) Pt(pt Pt) Pt {
 	}
 }
 
+// Linear applies only the linear part of the transform to a V, ignoring the
+// translation. This is the transform that applies to a derivative.
+func (t *T) Linear(v V) V {
+	return V{
+		v.X*t[0][0] + v.Y*t[0][1],
+		v.X*t[1][0] + v.Y*t[1][1],
+	}
+}
+
 // Slice applies the transform to a slice of Pts
 func (t *T) Slice(pts []Pt) []Pt {
 	out := make([]Pt, len(pts))
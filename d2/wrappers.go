@@ -0,0 +1,121 @@
+package d2
+
+// Segment returns the range [t0, t1] of c as a curve over the range [0,1]. If c
+// fulfills Segmenter that is used, otherwise c is wrapped in a SegmentWrapper.
+func Segment(c Pt1, t0, t1 float64) Pt1 {
+	if s, ok := c.(Segmenter); ok {
+		return s.Pt1Segment(t0, t1)
+	}
+	return SegmentWrapper{c, t0, t1}
+}
+
+// Reverse returns a curve that traces c in the opposite direction. If c
+// fulfills Reverser that is used, otherwise c is wrapped in a ReverseWrapper.
+func Reverse(c Pt1) Pt1 {
+	if r, ok := c.(Reverser); ok {
+		return r.Pt1Reverse()
+	}
+	return ReverseWrapper{c}
+}
+
+// Transform returns c with the transform applied. If c fulfills Transformer
+// that is used, otherwise c is wrapped in a TransformWrapper.
+func Transform(c Pt1, t *T) Pt1 {
+	if tr, ok := c.(Transformer); ok {
+		return tr.Pt1T(t)
+	}
+	return TransformWrapper{c, t}
+}
+
+// SegmentWrapper maps the range [T0, T1] of any Pt1 to the range [0,1].
+type SegmentWrapper struct {
+	P      Pt1
+	T0, T1 float64
+}
+
+func (s SegmentWrapper) t(t0 float64) float64 {
+	return s.T0 + t0*(s.T1-s.T0)
+}
+
+// Pt1 fulfills Pt1.
+func (s SegmentWrapper) Pt1(t0 float64) Pt {
+	return s.P.Pt1(s.t(t0))
+}
+
+// V1 fulfills V1.
+func (s SegmentWrapper) V1(t0 float64) V {
+	return GetV1(s.P).V1(s.t(t0)).Multiply(s.T1 - s.T0)
+}
+
+// V2 fulfills V2.
+func (s SegmentWrapper) V2(t0 float64) V {
+	d := s.T1 - s.T0
+	return GetV2(s.P).V2(s.t(t0)).Multiply(d * d)
+}
+
+// Pt1Segment fulfills Segmenter.
+func (s SegmentWrapper) Pt1Segment(t0, t1 float64) Pt1 {
+	return SegmentWrapper{s.P, s.t(t0), s.t(t1)}
+}
+
+// Pt1Reverse fulfills Reverser.
+func (s SegmentWrapper) Pt1Reverse() Pt1 {
+	return SegmentWrapper{s.P, s.T1, s.T0}
+}
+
+// ReverseWrapper traces any Pt1 in the opposite direction.
+type ReverseWrapper struct {
+	P Pt1
+}
+
+// Pt1 fulfills Pt1.
+func (r ReverseWrapper) Pt1(t0 float64) Pt {
+	return r.P.Pt1(1 - t0)
+}
+
+// V1 fulfills V1.
+func (r ReverseWrapper) V1(t0 float64) V {
+	return GetV1(r.P).V1(1 - t0).Multiply(-1)
+}
+
+// V2 fulfills V2.
+func (r ReverseWrapper) V2(t0 float64) V {
+	return GetV2(r.P).V2(1 - t0)
+}
+
+// Pt1Segment fulfills Segmenter.
+func (r ReverseWrapper) Pt1Segment(t0, t1 float64) Pt1 {
+	return SegmentWrapper{r.P, 1 - t0, 1 - t1}
+}
+
+// Pt1Reverse fulfills Reverser and returns the underlying curve.
+func (r ReverseWrapper) Pt1Reverse() Pt1 {
+	return r.P
+}
+
+// TransformWrapper applies a transform to any Pt1.
+type TransformWrapper struct {
+	P Pt1
+	T *T
+}
+
+// Pt1 fulfills Pt1.
+func (tw TransformWrapper) Pt1(t0 float64) Pt {
+	return tw.T.Pt(tw.P.Pt1(t0))
+}
+
+// V1 fulfills V1.
+func (tw TransformWrapper) V1(t0 float64) V {
+	return tw.T.Linear(GetV1(tw.P).V1(t0))
+}
+
+// V2 fulfills V2.
+func (tw TransformWrapper) V2(t0 float64) V {
+	return tw.T.Linear(GetV2(tw.P).V2(t0))
+}
+
+// Pt1T fulfills Transformer. The transforms are combined so that the
+// underlying curve is only wrapped once.
+func (tw TransformWrapper) Pt1T(t *T) Pt1 {
+	return TransformWrapper{tw.P, tw.T.T(t)}
+}
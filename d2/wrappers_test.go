@@ -3,6 +3,7 @@ package d2
 import (
 	"testing"
 
+	"github.com/adamcolton/geom/angle"
 	"github.com/adamcolton/geom/geomtest"
 	"github.com/stretchr/testify/assert"
 )
@@ -100,3 +101,57 @@ func TestGetPt2c1(t *testing.T) {
 	_, ok = pt2c1.(mockPt2c1)
 	assert.True(t, ok)
 }
+
+func TestSegmentWrapper(t *testing.T) {
+	c := mockCubic{}
+	s := Segment(c, 0.2, 0.6)
+	for i := 0.0; i <= 1.0; i += 0.25 {
+		geomtest.Equal(t, c.Pt1(0.2+i*0.4), s.Pt1(i))
+	}
+	geomtest.Equal(t, AssertV1{}, s.(Pt1V1))
+	geomtest.Equal(t, AssertV2{}, s)
+
+	s = Segment(s, 0.5, 1)
+	sw := s.(SegmentWrapper)
+	assert.Equal(t, c, sw.P)
+	geomtest.Equal(t, 0.4, sw.T0)
+	geomtest.Equal(t, 0.6, sw.T1)
+
+	r := Reverse(s)
+	geomtest.Equal(t, c.Pt1(0.6), r.Pt1(0))
+	geomtest.Equal(t, c.Pt1(0.4), r.Pt1(1))
+}
+
+func TestReverseWrapper(t *testing.T) {
+	c := mockCubic{}
+	r := Reverse(c)
+	for i := 0.0; i <= 1.0; i += 0.25 {
+		geomtest.Equal(t, c.Pt1(1-i), r.Pt1(i))
+	}
+	geomtest.Equal(t, AssertV1{}, r.(Pt1V1))
+	geomtest.Equal(t, AssertV2{}, r)
+	assert.Equal(t, c, Reverse(r))
+
+	s := Segment(r, 0.25, 0.5)
+	geomtest.Equal(t, c.Pt1(0.75), s.Pt1(0))
+	geomtest.Equal(t, c.Pt1(0.5), s.Pt1(1))
+}
+
+func TestTransformWrapper(t *testing.T) {
+	c := mockCubic{}
+	t0 := Translate(V{1, 2}).T()
+	t1 := Rotate(angle.Deg(90)).T()
+	tw := Transform(c, t0)
+	for i := 0.0; i <= 1.0; i += 0.25 {
+		geomtest.Equal(t, t0.Pt(c.Pt1(i)), tw.Pt1(i))
+	}
+	geomtest.Equal(t, AssertV1{}, tw.(Pt1V1))
+	geomtest.Equal(t, AssertV2{}, tw)
+
+	// t0 is applied before t1
+	tw = Transform(tw, t1)
+	assert.Equal(t, c, tw.(TransformWrapper).P)
+	for i := 0.0; i <= 1.0; i += 0.25 {
+		geomtest.Equal(t, t1.Pt(t0.Pt(c.Pt1(i))), tw.Pt1(i))
+	}
+}